@@ -8,12 +8,14 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"image/color"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 
 	"fyne.io/fyne/v2"
@@ -49,7 +51,6 @@ var (
 	Default 3x3 Pattern: A1-B1-C1-C2-C3
 	*/
 	INTERNAL_PATTERN_3x3 = []int{0, 1, 2, 5, 8}
-	PATTERN_3x3          *fynex.PatternInfo
 
 	/* 		 4x4
 		A	B	C	D
@@ -61,7 +62,6 @@ var (
 	Default 4x4 Pattern: A1-B1-C1-D1-D2-D3-D4
 	*/
 	INTERNAL_PATTERN_4x4 = []int{0, 1, 2, 3, 7, 11, 15}
-	PATTERN_4x4          *fynex.PatternInfo
 
 	/* 		   5x5
 		A	B	C	D	E
@@ -74,12 +74,17 @@ var (
 	Default 5x5 Pattern: 0 1 2 3 4 9 14 19 24
 	*/
 	INTERNAL_FRIENDLY_PATTERN_5x5 = "A1-B1-C1-D1-E1-E2-E3-E4-E5"
-	PATTERN_5x5                   *fynex.PatternInfo
+
+	// The default pattern of every grid. They are saved (hashed) in the
+	// pattern store on the first run, one demo user per grid.
+	defaultPatterns = map[fynex.PatternMode]*fynex.PatternInfo{}
+	demoModes       = []fynex.PatternMode{fynex.PatternMode3x3, fynex.PatternMode4x4, fynex.PatternMode5x5}
 
 	// CLI Flags
 	flgLog          bool
 	flgNoBackground bool
 	flgHelp         bool
+	flgSamples      string
 )
 
 const (
@@ -101,6 +106,12 @@ type patternApp struct {
 
 	current *activeSettings
 	ui      *uiStuff
+	// throttles failed attempts, shared by all PatternLock instances
+	lockout *fynex.AttemptLockout
+	// the hashed pattern of every grid, kept in the preferences
+	store fynex.PatternStore
+	// the plaintext patterns that are in the store, only those can be shown
+	known map[fynex.PatternMode]*fynex.PatternInfo
 }
 
 // currently active settings
@@ -136,18 +147,18 @@ func init() {
 
 	// Define 3x3 and 4x4 patterns using internal notation which is a slice
 	// of 0-based sequential indices
-	PATTERN_3x3, err = fynex.NewPattern(INTERNAL_PATTERN_3x3, fynex.PatternMode3x3)
+	defaultPatterns[fynex.PatternMode3x3], err = fynex.NewPattern(INTERNAL_PATTERN_3x3, fynex.PatternMode3x3)
 	if err != nil {
 		Die(2, err.Error())
 	}
 
-	PATTERN_4x4, err = fynex.NewPattern(INTERNAL_PATTERN_4x4, fynex.PatternMode4x4)
+	defaultPatterns[fynex.PatternMode4x4], err = fynex.NewPattern(INTERNAL_PATTERN_4x4, fynex.PatternMode4x4)
 	if err != nil {
 		Die(2, err.Error())
 	}
 
 	// Now let's define a 5x5 mode but using a human-friendly pattern notation
-	PATTERN_5x5, err = fynex.NewPatternFromString(INTERNAL_FRIENDLY_PATTERN_5x5, fynex.PatternMode5x5)
+	defaultPatterns[fynex.PatternMode5x5], err = fynex.NewPatternFromString(INTERNAL_FRIENDLY_PATTERN_5x5, fynex.PatternMode5x5)
 	if err != nil {
 		Die(2, err.Error())
 	}
@@ -172,7 +183,7 @@ func newPatternApp(pinfo *fynex.PatternInfo) *patternApp {
 			lockWidget:    nil,
 			lockContainer: nil,
 			radioButtons:  nil,
-			patternLabel:  widget.NewLabelWithStyle(pinfo.String(), fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
+			patternLabel:  widget.NewLabelWithStyle(describePattern(pinfo), fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
 		},
 	}
 }
@@ -195,6 +206,14 @@ func (a *patternApp) Define() *patternApp {
 	a.app = app.NewWithID(APP_ID)
 	a.win = a.app.NewWindow(APP_NAME)
 	a.win.SetMaster()
+	// 3 wrong patterns in a row lock the widget for 30s, 1m and then 5m.
+	// The counters survive a restart of the demo.
+	a.lockout = fynex.NewAttemptLockout(3).WithPreferences(a.app.Preferences(), "demo.lock")
+	// The patterns are stored hashed in the preferences as well, so the
+	// ones you define survive a restart.
+	a.store = fynex.NewPreferencesPatternStore(a.app.Preferences(), "demo.patterns")
+	a.seedStore()
+	a.current.pattern = a.known[a.current.mode]
 
 	// (Custom Widget)
 	// + ----------------
@@ -205,7 +224,7 @@ func (a *patternApp) Define() *patternApp {
 	// + ----------------
 	// And this is our initial setup with a known pattern
 	//lock = NewPatternLock(currentPattern.Size(), onCompleted)
-	a.ui.lockWidget = fynex.NewPatternLockWith(a.current.pattern, a.callbackOnValidated)
+	a.ui.lockWidget = a.newLockWidget(a.current.mode)
 	if a.current.useBackground {
 		a.ui.lockWidget.SetBackground(fynex.DefaultBackground)
 	}
@@ -245,6 +264,15 @@ func (a *patternApp) Define() *patternApp {
 	// |  A1-B1-C1-D1-E1-E2
 	// + -----------------------------------------
 	hintLabel := widget.NewLabelWithStyle("Current pattern:", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	showButton := widget.NewButton("Show", func() {
+		if a.current.pattern == nil {
+			log.Print("Playback: only the hash of the pattern is known")
+			return
+		}
+		if err := a.ui.lockWidget.Play(a.current.pattern, 0); err != nil {
+			log.Print("Playback: ", err)
+		}
+	})
 
 	slider := fynex.NewScrollableSlider(65, 90) // 'A'..'Z'
 	slider.OnConvert = func(f float64) string {
@@ -271,7 +299,7 @@ func (a *patternApp) Define() *patternApp {
 		a.ui.lockContainer,
 		container.NewCenter(a.ui.radioButtons),
 		container.NewCenter(bgRadioButtons),
-		hintLabel,
+		container.NewBorder(nil, nil, hintLabel, showButton),
 		a.ui.patternLabel,
 		//slider,
 		//a.ui.statusLED,
@@ -364,12 +392,48 @@ func (gui *uiStuff) replaceWidget(with *fynex.PatternLock, hasBackground bool) {
 	gui.lockContainer.Refresh()
 }
 
-func (a *patternApp) setupWidget(pinfo *fynex.PatternInfo, doSwap, altColor bool) {
+// saves the default patterns that are not in the store yet, and finds
+// out which defaults are still in use: only those can be shown.
+func (a *patternApp) seedStore() {
+	a.known = make(map[fynex.PatternMode]*fynex.PatternInfo)
+	for _, mode := range demoModes {
+		pinfo := defaultPatterns[mode]
+		record, err := a.store.Load(mode.String())
+		if errors.Is(err, fynex.ErrUnknownUser) {
+			if err := fynex.SavePattern(a.store, mode.String(), pinfo); err != nil {
+				log.Print("Pattern store: ", err)
+				continue
+			}
+			a.known[mode] = pinfo
+			continue
+		}
+		if err != nil {
+			log.Print("Pattern store: ", err)
+			continue
+		}
+		if fynex.VerifyPattern(record.Hash, pinfo.Pattern()) {
+			a.known[mode] = pinfo
+		}
+	}
+}
+
+// a PatternLock that validates against the stored pattern of the grid,
+// or the default one if the store cannot be read
+func (a *patternApp) newLockWidget(mode fynex.PatternMode) *fynex.PatternLock {
+	lockW := fynex.NewPatternLockWith(defaultPatterns[mode], a.callbackOnValidated)
+	if err := lockW.SetValidStoreEntry(a.store, mode.String()); err != nil {
+		log.Print("Validating the default pattern: ", err)
+	}
+	lockW.SetLockoutPolicy(a.lockout)
+	return lockW
+}
+
+func (a *patternApp) setupWidget(mode fynex.PatternMode, doSwap, altColor bool) {
 	GREEN := color.NRGBA{R: 0, G: 0x9d, B: 0, A: 255} // #009D00
-	a.current.pattern = pinfo
-	a.current.mode = pinfo.Mode()
-	a.ui.patternLabel.SetText(pinfo.String())
-	newLock := fynex.NewPatternLockWith(pinfo, a.callbackOnValidated)
+	a.current.pattern = a.known[mode]
+	a.current.mode = mode
+	a.ui.patternLabel.SetText(describePattern(a.current.pattern))
+	newLock := a.newLockWidget(mode)
 	if altColor {
 		newLock.SetSelectedColor(GREEN)
 	}
@@ -386,13 +450,13 @@ func (a *patternApp) callbackOnRadioChanged(s string) {
 	log.Printf("Selected %s", s)
 	switch s {
 	case fynex.PatternMode3x3.String():
-		a.setupWidget(PATTERN_3x3, true, true)
+		a.setupWidget(fynex.PatternMode3x3, true, true)
 
 	case fynex.PatternMode4x4.String():
-		a.setupWidget(PATTERN_4x4, true, true)
+		a.setupWidget(fynex.PatternMode4x4, true, true)
 
 	case fynex.PatternMode5x5.String():
-		a.setupWidget(PATTERN_5x5, true, true)
+		a.setupWidget(fynex.PatternMode5x5, true, true)
 
 	case "Define":
 		a.ui.patternLabel.SetText("None")
@@ -400,6 +464,11 @@ func (a *patternApp) callbackOnRadioChanged(s string) {
 		// different color for the drawn lines.
 		newLock := fynex.NewPatternLock(a.current.mode.Width(), a.callbackOnDefined)
 		newLock.EnterDesignState() // reconfigure for design-mode before we swap
+		// show a live strength meter and refuse weak patterns
+		newLock.SetStrengthMeter(true, fynex.StrengthFair)
+		newLock.OnDefineFailed = func(err error) {
+			log.Print("onDefineFailed (user) ", err)
+		}
 		a.ui.replaceWidget(newLock, a.current.useBackground)
 	}
 	a.ui.Refresh()
@@ -412,6 +481,10 @@ func (a *patternApp) callbackOnRadioChanged(s string) {
 func (a *patternApp) callbackOnCompleted(sequence []int) {
 	fmt.Printf("User drew: %v\n", sequence)
 	log.Printf("onCompleted DRAW %s", fynex.PatternInfoString(a.current.mode, sequence))
+	if a.current.pattern == nil {
+		fmt.Println("Only the hash of the pattern is known")
+		return
+	}
 	log.Printf("onCompleted REQD %s", a.current.pattern.String())
 	if reflect.DeepEqual(sequence, a.current.pattern.Pattern()) {
 		fmt.Println("Access granted")
@@ -456,8 +529,13 @@ func (a *patternApp) callbackOnDefined(sequence []int) {
 			// display human-readable pattern
 			a.ui.patternLabel.SetText(a.current.pattern.String())
 		*/
+		// only its hash is stored, we remember it until the demo ends
+		if err := fynex.SavePattern(a.store, a.current.mode.String(), newPattern); err != nil {
+			log.Print("Pattern store: ", err)
+		}
+		a.known[a.current.mode] = newPattern
 		a.ui.radioButtons.OnChanged = nil // prevent next tone from triggering
-		a.setupWidget(newPattern, true, true)
+		a.setupWidget(a.current.mode, true, true)
 		a.ui.radioButtons.SetSelected(a.current.mode.String())
 		a.ui.radioButtons.OnChanged = a.callbackOnRadioChanged
 	}
@@ -484,12 +562,20 @@ func ParseFlags() {
 	flag.BoolVar(&flgLog, "log", false, "View log output")
 	flag.BoolVar(&flgNoBackground, "nobg", false, "Don't use widget background")
 	flag.BoolVar(&flgHelp, "help", false, "Help!")
+	flag.StringVar(&flgSamples, "samples", "", "Write the sample pattern images to this directory and exit")
 	flag.Parse()
 
 	if flgHelp {
 		Help()
 	}
 
+	if flgSamples != "" {
+		if err := WriteSamples(flgSamples); err != nil {
+			Die(1, err.Error())
+		}
+		os.Exit(0)
+	}
+
 	if !flgLog {
 		log.SetOutput(io.Discard)
 	}
@@ -523,6 +609,31 @@ you to enjoy in the limited Fyne world.
 	about.ShowDialog()
 }
 
+// the pattern for the label, it may only be known by its hash
+func describePattern(pinfo *fynex.PatternInfo) string {
+	if pinfo == nil {
+		return "(hashed)"
+	}
+	return pinfo.String()
+}
+
+// renders the demo patterns as the sample_*.png images of the docs
+func WriteSamples(dir string) error {
+	for _, mode := range demoModes {
+		pinfo := defaultPatterns[mode]
+		res, err := fynex.RenderPatternPNG(pinfo, 300, 300, fynex.PatternImageOptions{Numbered: true})
+		if err != nil {
+			return err
+		}
+		filename := filepath.Join(dir, fmt.Sprintf("sample_%s.png", pinfo.Mode()))
+		if err := os.WriteFile(filename, res.Content(), 0o644); err != nil {
+			return err
+		}
+		fmt.Println("Wrote", filename)
+	}
+	return nil
+}
+
 // die with style
 func Die(exitCode int, message string) {
 	fmt.Println(message)
@@ -539,7 +650,7 @@ func main() {
 
 	ParseFlags()
 
-	app := newPatternApp(PATTERN_3x3)
+	app := newPatternApp(defaultPatterns[fynex.PatternMode3x3])
 	app.WithBackground(!flgNoBackground)
 	app.Define().Setup()
 
@@ -39,6 +39,7 @@ type AboutBox struct {
 	isMarkdown bool
 	centered   bool
 	container  *fyne.Container
+	messages   fynex.Messages
 }
 
 /* -----------------------------------------------------------------
@@ -62,6 +63,7 @@ func NewAboutBox(myWin fyne.Window, logoRes fyne.Resource, meta fyne.AppMetadata
 		isMarkdown: false,
 		centered:   false,
 		container:  nil,
+		messages:   nil,
 	}
 }
 
@@ -91,13 +93,22 @@ func (a *AboutBox) WithPersonModel(person *fynex.PersonModel) *AboutBox {
 	return a
 }
 
+// Overrides the dialog title (fynex.MsgAboutTitle) and the dismiss
+// button (fynex.MsgAboutClose), they are localized otherwise.
+func (a *AboutBox) WithMessages(messages fynex.Messages) *AboutBox {
+	a.messages = messages
+	return a
+}
+
 // Create an About window
 func (a *AboutBox) ShowDialog() {
 	if a.container == nil {
 		a.container = a.buildUI()
 	}
 
-	dialog.ShowCustom("About", "Close", a.container, a.parent)
+	dialog.ShowCustom(a.messages.Get(fynex.MsgAboutTitle),
+		a.messages.Get(fynex.MsgAboutClose),
+		a.container, a.parent)
 }
 
 func (a *AboutBox) buildUI() *fyne.Container {
@@ -0,0 +1,92 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * A headless harness for driving fynex widgets from application
+ * tests. It is built on Fyne's test driver: widgets are placed on an
+ * off-screen window and receive synthetic taps, drags, scrolls and
+ * key presses.
+ ********************************************************************/
+package fynextest
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// the current application, a headless test application is created if
+// there is none yet. Tests that need a fresh one per test should call
+// test.NewTempApp(t) before any other function of this package.
+func App() fyne.App {
+	if app := fyne.CurrentApp(); app != nil {
+		return app
+	}
+	return test.NewApp()
+}
+
+// Places the object alone on an unpadded off-screen window of its
+// minimum size, so that its own coordinates are the canvas coordinates.
+// The window is returned to inspect or close it.
+func Show(content fyne.CanvasObject) fyne.Window {
+	App()
+	w := test.NewWindow(content)
+	w.SetPadded(false)
+	w.Resize(content.MinSize())
+	return w
+}
+
+// a tap at the given position of the object. The object gets the focus
+// first when it is focusable, like with a real pointer.
+func Tap(obj fyne.Tappable, pos fyne.Position) {
+	test.TapAt(obj, pos)
+}
+
+// A drag along the path, each point being a Dragged event, followed by
+// DragEnd. Positions are relative to the object.
+func Drag(obj fyne.Draggable, path ...fyne.Position) {
+	if len(path) == 0 {
+		return
+	}
+	previous := path[0]
+	for _, pos := range path {
+		obj.Dragged(&fyne.DragEvent{
+			PointEvent: fyne.PointEvent{Position: pos, AbsolutePosition: pos},
+			Dragged:    fyne.NewDelta(pos.X-previous.X, pos.Y-previous.Y),
+		})
+		previous = pos
+	}
+	obj.DragEnd()
+}
+
+// a scroll of the mouse wheel (or touchpad) by the given distance,
+// dy > 0 scrolls forward.
+func Scroll(obj fyne.Scrollable, dx, dy float32) {
+	obj.Scrolled(&fyne.ScrollEvent{Scrolled: fyne.NewDelta(dx, dy)})
+}
+
+// types the text one rune at a time after focusing the object
+func Type(obj fyne.Focusable, text string) {
+	test.Type(obj, text)
+}
+
+// presses (and releases) the given keys in order
+func PressKey(obj fyne.Focusable, keys ...fyne.KeyName) {
+	for _, key := range keys {
+		obj.TypedKey(&fyne.KeyEvent{Name: key})
+	}
+}
+
+// moves the mouse into the object at the given position
+func Hover(obj desktop.Hoverable, pos fyne.Position) {
+	obj.MouseIn(&desktop.MouseEvent{PointEvent: fyne.PointEvent{Position: pos, AbsolutePosition: pos}})
+}
+
+// moves the mouse out of the object
+func Unhover(obj desktop.Hoverable) {
+	obj.MouseOut()
+}
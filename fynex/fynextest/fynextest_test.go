@@ -0,0 +1,534 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the harness itself, driving the fynex widgets the way an
+ * application test would: drawing, typing and locking out a
+ * PatternLock with the pointer or the keyboard, following its session
+ * events, trace and bindings, hovering it, and scrolling a
+ * ScrollableSlider.
+ ********************************************************************/
+package fynextest_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+	"github.com/lordofscripts/gofynex/fynex"
+	"github.com/lordofscripts/gofynex/fynex/fynextest"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+// the valid pattern of the tests, an L on a 3x3 grid
+const testPATTERN = "A1-A2-A3-B3-C3"
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestDrawPattern(t *testing.T) {
+	test.NewTempApp(t)
+	tests := []struct {
+		name     string
+		notation string
+		want     bool
+	}{
+		{"valid", testPATTERN, true},
+		{"reversed", "C3-B3-A3-A2-A1", false},
+		{"prefix", "A1-A2-A3-B3", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl, results := newValidatingLock(t)
+			if err := fynextest.DrawPattern(pl, tt.notation); err != nil {
+				t.Fatal(err)
+			}
+			if got := lastResult(t, results); got != tt.want {
+				t.Errorf("drawing %s validated %t, want %t", tt.notation, got, tt.want)
+			}
+		})
+	}
+
+	pl, _ := newValidatingLock(t)
+	if err := fynextest.DrawPattern(pl, "A1-D4"); err == nil {
+		t.Error("a pattern off the grid was drawn")
+	}
+}
+
+func TestDrawPatternHash(t *testing.T) {
+	test.NewTempApp(t)
+	pattern, _ := fynex.NewPatternFromString(testPATTERN, fynex.PatternMode3x3)
+	hash, err := fynex.HashPattern(pattern, fynex.HashParams{Iterations: 1000, SaltLength: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := make(chan bool, 4)
+	pl := fynex.NewPatternLockWithHash(hash, func(valid bool) { results <- valid })
+	fynextest.Show(pl)
+
+	// the hash is verified in the background, the harness waits for it
+	fynextest.DrawPattern(pl, testPATTERN)
+	if !lastResult(t, results) {
+		t.Error("the hashed pattern was not granted")
+	}
+	fynextest.DrawPattern(pl, "A1-B1-C1")
+	if lastResult(t, results) {
+		t.Error("a wrong pattern was granted")
+	}
+}
+
+func TestIdentitiesAndDuress(t *testing.T) {
+	test.NewTempApp(t)
+	params := fynex.HashParams{Iterations: 1000, SaltLength: 8}
+	hashOf := func(notation string) *fynex.PatternHash {
+		pattern, _ := fynex.NewPatternFromString(notation, fynex.PatternMode3x3)
+		hash, err := fynex.HashPattern(pattern, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	results := make(chan bool, 4)
+	pl := fynex.NewPatternLockWithHash(hashOf(testPATTERN), func(valid bool) { results <- valid })
+	if err := pl.EnrollIdentityHash("alice", hashOf("A1-B1-C1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := pl.SetDuressHash(hashOf("A1-B2-C3")); err != nil {
+		t.Fatal(err)
+	}
+	who := ""
+	pl.OnIdentified = func(userID string) { who = userID }
+	pl.OnDuress = func() { who = "duress" }
+	fynextest.Show(pl)
+
+	// every secret is a hash, verified in the background
+	tests := []struct {
+		notation string
+		want     bool
+		wantWho  string
+	}{
+		{testPATTERN, true, ""},
+		{"A1-B1-C1", true, "alice"},
+		{"A1-B2-C3", true, "duress"},
+		{"C3-B2-A1", false, ""},
+	}
+	for _, tt := range tests {
+		who = ""
+		fynextest.DrawPattern(pl, tt.notation)
+		if got := lastResult(t, results); got != tt.want || who != tt.wantWho {
+			t.Errorf("drawing %s validated %t for %q, want %t for %q", tt.notation, got, who, tt.want, tt.wantWho)
+		}
+	}
+}
+
+func TestNoJumpsFill(t *testing.T) {
+	test.NewTempApp(t)
+	tests := []struct {
+		name  string
+		rules fynex.PatternRule
+		want  bool
+	}{
+		// the corners skip A2 and B3, the drag only passes them
+		{"defaults", nil, false},
+		{"no jumps", fynex.NoJumps(), true},
+		{"nested", fynex.PatternRules{fynex.MinLength(4), fynex.NoJumps()}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl, results := newValidatingLock(t)
+			pl.SetPatternRules(tt.rules)
+			fynextest.DrawPattern(pl, "A1-A3-C3")
+			if got := lastResult(t, results); got != tt.want {
+				t.Errorf("drawing A1-A3-C3 validated %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypePattern(t *testing.T) {
+	test.NewTempApp(t)
+	tests := []struct {
+		name     string
+		notation string
+		want     bool
+	}{
+		{"friendly", testPATTERN, true},
+		{"lower case", "a1-a2-a3-b3-c3", true},
+		{"wrong", "A1-B1-C1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl, results := newValidatingLock(t)
+			fynextest.TypePattern(pl, tt.notation)
+			if got := lastResult(t, results); got != tt.want {
+				t.Errorf("typing %s validated %t, want %t", tt.notation, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockout(t *testing.T) {
+	test.NewTempApp(t)
+	pl, results := newValidatingLock(t)
+	pl.SetLockoutPolicy(fynex.NewAttemptLockout(2, time.Hour))
+
+	fynextest.DrawPattern(pl, "A1-B1-C1")
+	lastResult(t, results)
+	if pl.IsLockedOut() {
+		t.Fatal("locked out after one failure")
+	}
+	fynextest.TypePattern(pl, "A1-B1-C1")
+	lastResult(t, results)
+	if !pl.IsLockedOut() {
+		t.Fatal("not locked out after two failures")
+	}
+
+	// even the valid pattern is ignored now
+	fynextest.DrawPattern(pl, testPATTERN)
+	fynextest.TypePattern(pl, testPATTERN)
+	select {
+	case valid := <-results:
+		t.Errorf("a locked out widget validated a pattern (%t)", valid)
+	default:
+	}
+}
+
+func TestTapDot(t *testing.T) {
+	test.NewTempApp(t)
+	pl := fynextest.NewPatternLock(fynex.PatternMode4x4, nil)
+	for _, dot := range []string{"B2", "c3", " D4 "} {
+		if err := fynextest.TapDot(pl, dot); err != nil {
+			t.Fatalf("TapDot(%q) error = %v", dot, err)
+		}
+	}
+	if want := []int{5, 10, 15}; !slices.Equal(pl.Sequence, want) {
+		t.Errorf("tapped %v, want %v", pl.Sequence, want)
+	}
+	for _, dot := range []string{"E1", "A5", "Z", "A12"} {
+		if err := fynextest.TapDot(pl, dot); err == nil {
+			t.Errorf("TapDot(%q) accepted a dot off the grid", dot)
+		}
+	}
+}
+
+func TestDotCenters(t *testing.T) {
+	test.NewTempApp(t)
+	pl := fynextest.NewPatternLock(fynex.PatternMode3x3, nil)
+	centers, err := fynextest.DotCenters(pl, "A1-B1-C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(centers) != 3 || !(centers[0].X < centers[1].X && centers[1].X < centers[2].X) {
+		t.Errorf("dot centers %v are not left to right", centers)
+	}
+	if centers[0].Y != centers[2].Y {
+		t.Errorf("dot centers %v are not on one row", centers)
+	}
+}
+
+func TestScrollSlider(t *testing.T) {
+	test.NewTempApp(t)
+	slider := fynextest.NewScrollableSlider(0, 10)
+	changes := 0
+	slider.OnValueChanged = func(float64) { changes++ }
+
+	steps := []struct {
+		notches int
+		want    float64
+	}{
+		{3, 3},
+		{-1, 2},
+		{20, 10}, // stops at the maximum
+		{-30, 0}, // and at the minimum
+	}
+	for _, step := range steps {
+		fynextest.ScrollSlider(slider, step.notches)
+		if got := slider.GetValue(); got != step.want {
+			t.Errorf("after %d notches the value is %g, want %g", step.notches, got, step.want)
+		}
+	}
+	if changes != len(steps) {
+		t.Errorf("OnValueChanged was called %d times, want %d", changes, len(steps))
+	}
+}
+
+func TestSessionEvents(t *testing.T) {
+	test.NewTempApp(t)
+	tests := []struct {
+		name    string
+		gesture func(pl *fynex.PatternLock)
+		want    []string
+	}{
+		{"completed", func(pl *fynex.PatternLock) {
+			fynextest.DrawPattern(pl, "A1-A2-A3")
+		}, []string{"start", "dot 0", "dot 3", "dot 6", "trace Completed", "complete", "validated false"}},
+		{"cancelled", func(pl *fynex.PatternLock) {
+			// released away from every dot
+			fynextest.Drag(pl, fyne.NewPos(1, 1), fyne.NewPos(2, 2))
+		}, []string{"start", "trace Cancelled", "cancel"}},
+		{"reset", func(pl *fynex.PatternLock) {
+			pl.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: pl.DotCenter(4)}})
+			pl.Reset()
+		}, []string{"start", "dot 4", "trace Reset", "reset"}},
+		{"reset without drawing", func(pl *fynex.PatternLock) {
+			pl.Reset()
+		}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl, _ := newValidatingLock(t)
+			events := []string{}
+			record := func(event string) { events = append(events, event) }
+			pl.OnStart = func() { record("start") }
+			pl.OnDotAdded = func(index int, position fyne.Position) {
+				if position != pl.DotCenter(index) {
+					t.Errorf("dot %d added at %v, its center is %v", index, position, pl.DotCenter(index))
+				}
+				record(fmt.Sprint("dot ", index))
+			}
+			pl.OnTrace = func(trace *fynex.PatternTrace) { record("trace " + trace.Outcome.String()) }
+			pl.OnComplete = func([]int) { record("complete") }
+			pl.OnCancel = func() { record("cancel") }
+			pl.OnReset = func() { record("reset") }
+			pl.OnValidated = func(valid bool) { record(fmt.Sprint("validated ", valid)) }
+
+			tt.gesture(pl)
+			fynextest.WaitVerified(pl)
+			if !slices.Equal(events, tt.want) {
+				t.Errorf("events %q, want %q", events, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrace(t *testing.T) {
+	test.NewTempApp(t)
+	const pause = 20 * time.Millisecond
+	pl, _ := newValidatingLock(t)
+	var during *fynex.PatternTrace
+	pl.OnComplete = func([]int) { during = pl.LastTrace() }
+	if pl.LastTrace() != nil {
+		t.Fatal("a trace before the first drawing")
+	}
+
+	for _, dot := range []int{0, 3, 6} {
+		pl.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: pl.DotCenter(dot)}})
+		time.Sleep(pause)
+	}
+	pl.DragEnd()
+	fynextest.WaitVerified(pl)
+
+	trace := pl.LastTrace()
+	if trace == nil || trace != during {
+		t.Fatal("the trace was not set when OnComplete ran")
+	}
+	if trace.Outcome != fynex.TraceCompleted || trace.Mode != fynex.PatternMode3x3 {
+		t.Errorf("trace %s of %s, want Completed of 3x3", trace.Outcome, trace.Mode)
+	}
+	if !slices.Equal(trace.Sequence(), []int{0, 3, 6}) {
+		t.Errorf("trace sequence %v, want [0 3 6]", trace.Sequence())
+	}
+	intervals := trace.Intervals()
+	if len(intervals) != 2 || intervals[0] < pause || intervals[1] < pause {
+		t.Errorf("intervals %v, want two of at least %s", intervals, pause)
+	}
+	if trace.Duration() < 3*pause || trace.Dots[0].At.Before(trace.Started) || trace.Ended.Before(trace.Dots[2].At) {
+		t.Errorf("session from %s to %s took %s, dots at %v", trace.Started, trace.Ended, trace.Duration(), trace.Dots)
+	}
+
+	// the dots added in pass-through mode take no time
+	pl.SetPassThrough(true)
+	pl.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: pl.DotCenter(0)}})
+	time.Sleep(pause)
+	pl.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: pl.DotCenter(8)}})
+	pl.DragEnd()
+	fynextest.WaitVerified(pl)
+	trace = pl.LastTrace()
+	intervals = trace.Intervals()
+	if !slices.Equal(trace.Sequence(), []int{0, 4, 8}) || intervals[0] < pause || intervals[1] >= intervals[0] {
+		t.Errorf("pass-through trace %v with intervals %v", trace.Sequence(), intervals)
+	}
+}
+
+func TestHover(t *testing.T) {
+	test.NewTempApp(t)
+	pl := fynextest.NewPatternLock(fynex.PatternMode3x3, nil)
+	renderer := test.TempWidgetRenderer(t, pl)
+	// the center of the visible hover ring, false without one
+	ring := func() (fyne.Position, bool) {
+		renderer.Refresh()
+		for _, obj := range renderer.Objects() {
+			circle, ok := obj.(*canvas.Circle)
+			if ok && circle.Visible() && circle.StrokeWidth > 0 && circle.StrokeColor == pl.Style().HoverColor {
+				return circle.Position().AddXY(circle.Size().Width/2, circle.Size().Height/2), true
+			}
+		}
+		return fyne.Position{}, false
+	}
+
+	b2, c2 := pl.DotCenter(4), pl.DotCenter(5)
+	fynextest.Hover(pl, b2.AddXY(5, -5))
+	center, shown := ring()
+	if off := center.Subtract(b2); !shown || off.X*off.X+off.Y*off.Y > 1 {
+		t.Errorf("ring shown %t around %v, want it around B2 at %v", shown, center, b2)
+	}
+	// halfway between two dots is outside both hit areas
+	fynextest.Hover(pl, fyne.NewPos((b2.X+c2.X)/2, b2.Y))
+	if _, shown := ring(); shown {
+		t.Error("a ring is shown between the dots")
+	}
+	fynextest.Hover(pl, pl.DotCenter(0))
+	fynextest.Unhover(pl)
+	if _, shown := ring(); shown {
+		t.Error("the ring stayed after the mouse left")
+	}
+}
+
+func TestKeyboardCursor(t *testing.T) {
+	test.NewTempApp(t)
+	rectangle, _ := fynex.NewPatternMode(5, 3)
+	tests := []struct {
+		name  string
+		mode  fynex.PatternMode
+		keys  []fyne.KeyName // then Space adds the dot under the cursor
+		want  int
+		where string // the dot named in the status
+	}{
+		{"start", fynex.PatternMode3x3, nil, 0, "A1"},
+		{"right", fynex.PatternMode3x3, []fyne.KeyName{fyne.KeyRight}, 1, "B1"},
+		{"right edge", fynex.PatternMode3x3, []fyne.KeyName{fyne.KeyRight, fyne.KeyRight, fyne.KeyRight}, 2, "C1"},
+		{"down", fynex.PatternMode3x3, []fyne.KeyName{fyne.KeyDown}, 3, "A2"},
+		{"bottom edge", fynex.PatternMode3x3, []fyne.KeyName{fyne.KeyDown, fyne.KeyDown, fyne.KeyDown, fyne.KeyRight}, 7, "B3"},
+		{"left edge", fynex.PatternMode3x3, []fyne.KeyName{fyne.KeyLeft, fyne.KeyDown}, 3, "A2"},
+		{"top edge", fynex.PatternMode3x3, []fyne.KeyName{fyne.KeyRight, fyne.KeyUp, fyne.KeyUp}, 1, "B1"},
+		{"wide grid", rectangle, []fyne.KeyName{fyne.KeyRight, fyne.KeyRight, fyne.KeyRight, fyne.KeyRight,
+			fyne.KeyRight, fyne.KeyDown, fyne.KeyDown, fyne.KeyDown}, 14, "E3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := fynextest.NewPatternLock(tt.mode, nil)
+			fynextest.PressKey(pl, tt.keys...)
+			if len(tt.keys) != 0 && !strings.Contains(pl.Status, tt.where) {
+				t.Errorf("status %q does not name %s", pl.Status, tt.where)
+			}
+			fynextest.PressKey(pl, fyne.KeySpace)
+			if !slices.Equal(pl.Sequence, []int{tt.want}) {
+				t.Errorf("Space added %v, want [%d]", pl.Sequence, tt.want)
+			}
+			// a visited dot is not added again
+			fynextest.PressKey(pl, fyne.KeySpace)
+			if len(pl.Sequence) != 1 {
+				t.Errorf("Space on a visited dot gave %v", pl.Sequence)
+			}
+		})
+	}
+}
+
+func TestKeyboardSubmit(t *testing.T) {
+	test.NewTempApp(t)
+	pl, results := newValidatingLock(t)
+	resets := 0
+	pl.OnReset = func() { resets++ }
+
+	// A1-A2-A3-B3-C3 with the cursor, submitted with Enter
+	fynextest.PressKey(pl, fyne.KeySpace, fyne.KeyDown, fyne.KeySpace, fyne.KeyDown, fyne.KeySpace,
+		fyne.KeyRight, fyne.KeySpace, fyne.KeyRight, fyne.KeySpace, fyne.KeyReturn)
+	fynextest.WaitVerified(pl)
+	if !lastResult(t, results) {
+		t.Error("the pattern entered with the cursor was not granted")
+	}
+	if trace := pl.LastTrace(); trace == nil || !slices.Equal(trace.Sequence(), []int{0, 3, 6, 7, 8}) {
+		t.Errorf("trace %v, want the dots added with Space", trace)
+	}
+
+	// Escape clears the dots and the typed notation
+	fynextest.PressKey(pl, fyne.KeySpace, fyne.KeyUp, fyne.KeySpace)
+	fynextest.Type(pl, "A1-")
+	fynextest.PressKey(pl, fyne.KeyEscape)
+	if len(pl.Sequence) != 0 || resets != 1 {
+		t.Errorf("after Escape the sequence is %v with %d resets, want empty and 1", pl.Sequence, resets)
+	}
+	// so Enter has nothing to submit
+	fynextest.PressKey(pl, fyne.KeyEnter)
+	fynextest.WaitVerified(pl)
+	select {
+	case valid := <-results:
+		t.Errorf("Enter after Escape validated a pattern (%t)", valid)
+	default:
+	}
+}
+
+func TestBindings(t *testing.T) {
+	test.NewTempApp(t)
+	pl, _ := newValidatingLock(t)
+	status, sequence := pl.StatusBinding(), pl.SequenceBinding()
+	observed := func() []int {
+		t.Helper()
+		dots, err := sequence.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dots
+	}
+
+	// the status goes both ways
+	pl.SetStatus("Draw your pattern")
+	if got, _ := status.Get(); got != "Draw your pattern" {
+		t.Errorf("status binding %q after SetStatus()", got)
+	}
+	status.Set("Set through the binding")
+	if pl.Status != "Set through the binding" {
+		t.Errorf("status %q after setting the binding", pl.Status)
+	}
+
+	// the sequence follows the drawing, dot by dot
+	for i, notation := range []string{"A1", "A2", "A3"} {
+		if err := fynextest.TapDot(pl, notation); err != nil {
+			t.Fatal(err)
+		}
+		if got := observed(); len(got) != i+1 || !slices.Equal(got, pl.Sequence) {
+			t.Errorf("sequence binding %v after tapping %s, want %v", got, notation, pl.Sequence)
+		}
+	}
+	if got, _ := status.Get(); got != pl.Status {
+		t.Errorf("status binding %q, the widget shows %q", got, pl.Status)
+	}
+	pl.Reset()
+	if got := observed(); len(got) != 0 {
+		t.Errorf("sequence binding %v after Reset(), want it empty", got)
+	}
+}
+
+// an off-screen PatternLock for testPATTERN that reports its verdicts
+func newValidatingLock(t *testing.T) (*fynex.PatternLock, chan bool) {
+	t.Helper()
+	pattern, err := fynex.NewPatternFromString(testPATTERN, fynex.PatternMode3x3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := make(chan bool, 4)
+	pl := fynextest.NewPatternLockWith(pattern, func(valid bool) { results <- valid })
+	return pl, results
+}
+
+// the verdict of the pattern just drawn, the test fails without one
+func lastResult(t *testing.T, results chan bool) bool {
+	t.Helper()
+	select {
+	case valid := <-results:
+		return valid
+	default:
+		t.Fatal("OnValidated was not called")
+	}
+	return false
+}
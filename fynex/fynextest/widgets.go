@@ -0,0 +1,168 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Off-screen constructors and input helpers for the fynex widgets:
+ * PatternLock, ScrollableSlider, LedLabel and DynamicLabel.
+ ********************************************************************/
+package fynextest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/lordofscripts/gofynex/fynex"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// the scroll distance of one mouse wheel notch
+	ScrollStep = 25
+	// the longest wait for a drawn pattern to be verified
+	VerifyTimeout = 5 * time.Second
+)
+
+/* -----------------------------------------------------------------
+ *                  C O N S T R U C T O R S
+ * -----------------------------------------------------------------*/
+
+// (ctor) an off-screen PatternLock for the grid, see fynex.NewPatternLockFor()
+func NewPatternLock(mode fynex.PatternMode, onComplete func([]int)) *fynex.PatternLock {
+	pl := fynex.NewPatternLockFor(mode, onComplete)
+	Show(pl)
+	return pl
+}
+
+// (ctor) an off-screen PatternLock that validates against the pattern,
+// see fynex.NewPatternLockWith()
+func NewPatternLockWith(pi *fynex.PatternInfo, onValidated func(bool)) *fynex.PatternLock {
+	pl := fynex.NewPatternLockWith(pi, onValidated)
+	Show(pl)
+	return pl
+}
+
+// (ctor) an off-screen ScrollableSlider
+func NewScrollableSlider(min, max float64) *fynex.ScrollableSlider {
+	s := fynex.NewScrollableSlider(min, max)
+	Show(s)
+	return s
+}
+
+// (ctor) an off-screen LedLabel
+func NewLedLabel(text string) *fynex.LedLabel {
+	ll := fynex.NewLedLabel(text)
+	Show(ll)
+	return ll
+}
+
+// (ctor) an off-screen DynamicLabel
+func NewDynamicLabel(text string, onChanged func(string)) *fynex.DynamicLabel {
+	d := fynex.NewDynamicLabel(text, onChanged)
+	Show(d)
+	return d
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// Drags the pointer over the dots of the pattern (A1-B2-C3 notation)
+// and releases it, exactly like a user would. The notation must be a
+// valid pattern for the widget's grid.
+func DrawPattern(pl *fynex.PatternLock, notation string) error {
+	sequence, err := fynex.ParseStringPatternFor(notation, pl.Mode())
+	if err != nil {
+		return err
+	}
+	DrawSequence(pl, sequence)
+	return nil
+}
+
+// Drags the pointer over the dots given as internal indices and
+// releases it. Unlike DrawPattern() anything goes, even sequences that
+// are not valid patterns, to exercise the refusals. It returns once the
+// pattern was verified, see WaitVerified().
+func DrawSequence(pl *fynex.PatternLock, sequence []int) {
+	path := make([]fyne.Position, 0, len(sequence))
+	for _, index := range sequence {
+		path = append(path, pl.DotCenter(index))
+	}
+	Drag(pl, path...)
+	WaitVerified(pl)
+}
+
+// the centers of the dots of the pattern (A1-B2-C3 notation) in widget
+// coordinates, the same geometry the widget uses to hit-test the pointer
+func DotCenters(pl *fynex.PatternLock, notation string) ([]fyne.Position, error) {
+	sequence, err := fynex.ParseStringPatternFor(notation, pl.Mode())
+	if err != nil {
+		return nil, err
+	}
+	centers := make([]fyne.Position, 0, len(sequence))
+	for _, index := range sequence {
+		centers = append(centers, pl.DotCenter(index))
+	}
+	return centers, nil
+}
+
+// taps a single dot given by name like "B2"
+func TapDot(pl *fynex.PatternLock, dot string) error {
+	index, err := dotIndex(pl.Mode(), dot)
+	if err != nil {
+		return err
+	}
+	Tap(pl, pl.DotCenter(index))
+	return nil
+}
+
+// Types the pattern (A1-B2-C3 notation) on the keyboard and submits
+// it with Enter. It returns once the pattern was verified.
+func TypePattern(pl *fynex.PatternLock, notation string) {
+	Type(pl, notation)
+	PressKey(pl, fyne.KeyReturn)
+	WaitVerified(pl)
+}
+
+// Waits until the drawn pattern was verified in the background and
+// OnValidated was called, at most VerifyTimeout. It reports whether the
+// verification is over.
+func WaitVerified(pl *fynex.PatternLock) bool {
+	deadline := time.Now().Add(VerifyTimeout)
+	for pl.IsVerifying() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+// scrolls the slider by the given number of mouse wheel notches,
+// negative values scroll backwards
+func ScrollSlider(s *fynex.ScrollableSlider, notches int) {
+	Scroll(s, 0, float32(notches*ScrollStep))
+}
+
+// taps the label to fire its OnTapped callback
+func TapLabel(d *fynex.DynamicLabel) {
+	test.Tap(d)
+}
+
+// the internal index of a dot name like "B2" on the grid
+func dotIndex(mode fynex.PatternMode, dot string) (int, error) {
+	dot = strings.ToUpper(strings.TrimSpace(dot))
+	if len(dot) != 2 {
+		return -1, fmt.Errorf("invalid dot '%s'", dot)
+	}
+	column, row := int(dot[0]-'A'), int(dot[1]-'1')
+	if column < 0 || column >= mode.Columns() || row < 0 || row >= mode.Rows() {
+		return -1, fmt.Errorf("dot '%s' is not on a %s grid", dot, mode)
+	}
+	return row*mode.Columns() + column, nil
+}
@@ -0,0 +1,431 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Locks any fyne.Window after a period of keyboard and mouse
+ * inactivity. The content is covered by a full-window PatternLock
+ * and it is only uncovered when the right pattern is drawn.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"image/color"
+	"log"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/driver/mobile"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+// the longest interval between two idle checks
+const idleCHECK_INTERVAL = time.Second
+
+// the shortest interval between two idle checks
+const idleMIN_CHECK_INTERVAL = 10 * time.Millisecond
+
+// the part of the timeout without activity before the sensor is put
+// back on top of the content
+const idleSENSOR_REARM = 2
+
+/* -----------------------------------------------------------------
+ *                     I N T E R F A C E S
+ * -----------------------------------------------------------------*/
+
+var _ fyne.Widget = (*activitySensor)(nil)
+var _ desktop.Hoverable = (*activitySensor)(nil)
+var _ mobile.Touchable = (*activitySensor)(nil)
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// Watches a window for inactivity and covers it with a PatternLock
+// when the timeout expires. Pointer movement, touches, keys typed when
+// no widget has the focus and focus changes count as activity. Keys
+// typed into a focused widget (e.g. an Entry) are not seen by the
+// window, call Poke() from its OnChanged to count them as well. While
+// locked the main menu and the window shortcuts are disabled.
+type IdleLocker struct {
+	// called on the main goroutine when the window gets locked
+	OnLocked func()
+	// called on the main goroutine when the right pattern was drawn
+	OnUnlocked func()
+
+	window       fyne.Window
+	lock         *PatternLock
+	cover        fyne.CanvasObject // the overlay with the PatternLock
+	background   *canvas.Rectangle // hides the content while locked
+	sensor       *activitySensor
+	content      fyne.CanvasObject // the window content before Start()
+	timeout      time.Duration
+	lastActivity time.Time
+	armedAt      time.Time // the sensor was put back on top
+	locked       bool
+	running      bool
+	stop         chan struct{}
+	retime       chan time.Duration // a new check interval for the watcher
+	prevFocus    fyne.Focusable     // focused when locked, focused again when unlocked
+	lastFocus    fyne.Focusable     // focused at the previous check
+	keyChain     keyHandlers        // the canvas handlers before Start()
+	menu         *fyne.MainMenu     // removed while locked
+	mux          sync.Mutex
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// the keyboard handlers of a canvas
+type keyHandlers struct {
+	typedKey  func(*fyne.KeyEvent)
+	typedRune func(rune)
+	keyDown   func(*fyne.KeyEvent)
+}
+
+// A transparent widget on top of the content that notices the pointer.
+// It gets out of the way as soon as it did, so that the widgets below
+// receive the following events. It is only back on top after half the
+// timeout without activity, as it takes the hover from the content.
+type activitySensor struct {
+	widget.BaseWidget
+	onActivity func()
+}
+
+/* -----------------------------------------------------------------
+ *                  C O N S T R U C T O R S
+ * -----------------------------------------------------------------*/
+
+// (ctor) an idle locker for the window that asks for the pattern after
+// the timeout without activity. Nothing is watched until Start().
+func NewIdleLocker(win fyne.Window, pattern *PatternInfo, timeout time.Duration) (*IdleLocker, error) {
+	if win == nil {
+		return nil, errors.New("idle locker needs a window")
+	}
+	if pattern == nil || !pattern.Mode().IsValid() {
+		return nil, errors.New("idle locker needs a valid pattern")
+	}
+	if timeout <= 0 {
+		return nil, errors.New("idle locker needs a positive timeout")
+	}
+	il := &IdleLocker{
+		window:  win,
+		timeout: timeout,
+	}
+	il.lock = NewPatternLockWith(pattern, il.onValidated)
+	il.background = canvas.NewRectangle(theme.Color(theme.ColorNameBackground))
+	il.cover = container.NewStack(il.background, il.lock)
+	il.sensor = newActivitySensor(il.Poke)
+	return il, nil
+}
+
+// (ctor) the transparent pointer sensor
+func newActivitySensor(onActivity func()) *activitySensor {
+	s := &activitySensor{onActivity: onActivity}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// Starts watching the window, call it after SetContent(). The content
+// is wrapped to notice the pointer, setting a new content afterwards
+// needs Stop() and Start() again. Call it on the main goroutine.
+func (il *IdleLocker) Start() *IdleLocker {
+	il.mux.Lock()
+	if il.running {
+		il.mux.Unlock()
+		return il
+	}
+	il.running = true
+	il.lastActivity = time.Now()
+	il.armedAt = il.lastActivity
+	il.stop = make(chan struct{})
+	il.retime = make(chan time.Duration, 1)
+	interval := checkInterval(il.timeout)
+	stop, retime := il.stop, il.retime
+	il.mux.Unlock()
+
+	il.content = il.window.Content()
+	il.sensor.Show()
+	il.window.SetContent(container.NewStack(il.content, il.sensor))
+	il.chainKeys()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case interval := <-retime:
+				ticker.Reset(interval)
+			case <-ticker.C:
+				fyne.Do(il.check)
+			}
+		}
+	}()
+	return il
+}
+
+// Stops watching the window and gives it its original content back. A
+// locked window stays locked until the pattern is drawn. Call it on
+// the main goroutine.
+func (il *IdleLocker) Stop() {
+	il.mux.Lock()
+	if !il.running {
+		il.mux.Unlock()
+		return
+	}
+	il.running = false
+	close(il.stop)
+	il.mux.Unlock()
+
+	il.unchainKeys()
+	il.window.SetContent(il.content)
+}
+
+// Locks the window right away, e.g. from a "Lock" menu item. Call it
+// on the main goroutine.
+func (il *IdleLocker) Lock() {
+	il.mux.Lock()
+	if il.locked {
+		il.mux.Unlock()
+		return
+	}
+	il.locked = true
+	onLocked := il.OnLocked
+	il.mux.Unlock()
+
+	log.Print("Idle lock")
+	il.menu = il.window.MainMenu()
+	il.window.SetMainMenu(nil)
+	il.lock.blockShortcuts(true)
+	c := il.window.Canvas()
+	il.prevFocus = c.Focused()
+	il.background.FillColor = theme.Color(theme.ColorNameBackground)
+	il.lock.clearDrawing()
+	il.lock.SetStatus(il.lock.message(MsgStatusUnblock))
+	c.Overlays().Add(il.cover)
+	c.Focus(il.lock)
+	if onLocked != nil {
+		onLocked()
+	}
+}
+
+// whether the window is covered by the PatternLock
+func (il *IdleLocker) IsLocked() bool {
+	il.mux.Lock()
+	defer il.mux.Unlock()
+
+	return il.locked
+}
+
+// Counts as activity, the timeout starts again. It can be called from
+// any goroutine.
+func (il *IdleLocker) Poke() {
+	il.mux.Lock()
+	il.lastActivity = time.Now()
+	il.mux.Unlock()
+}
+
+// Changes the inactivity that locks the window. A running locker
+// checks more often right away if the new timeout needs it.
+func (il *IdleLocker) SetTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errors.New("idle locker needs a positive timeout")
+	}
+	il.mux.Lock()
+	defer il.mux.Unlock()
+
+	il.timeout = timeout
+	if il.running {
+		// only sent under the lock, the old interval makes room
+		select {
+		case <-il.retime:
+		default:
+		}
+		il.retime <- checkInterval(timeout)
+	}
+	return nil
+}
+
+// The PatternLock of the overlay, to set its style, messages or lockout
+// policy. Its OnValidated callback belongs to the IdleLocker.
+func (il *IdleLocker) PatternLock() *PatternLock {
+	return il.lock
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// Locks the window once the timeout expired (main goroutine). The
+// pointer is only watched while the sensor is on top, so the timeout
+// counts from the latest of the activity and the sensor coming back.
+func (il *IdleLocker) check() {
+	focused := il.window.Canvas().Focused()
+	armed := il.sensor.Visible()
+	now := time.Now()
+	il.mux.Lock()
+	if !il.running || il.locked {
+		il.mux.Unlock()
+		return
+	}
+	if focused != il.lastFocus {
+		// a click or a tab moved the focus
+		il.lastFocus = focused
+		il.lastActivity = now
+	}
+	rearm := !armed && now.Sub(il.lastActivity) >= il.timeout/idleSENSOR_REARM
+	if rearm {
+		il.armedAt = now
+	}
+	watched := il.lastActivity
+	if il.armedAt.After(watched) {
+		watched = il.armedAt
+	}
+	idle := armed && now.Sub(watched) >= il.timeout
+	il.mux.Unlock()
+
+	if rearm {
+		// back on top to notice the next movement
+		il.sensor.Show()
+	}
+	if idle {
+		il.Lock()
+	}
+}
+
+// the PatternLock was drawn (main goroutine)
+func (il *IdleLocker) onValidated(valid bool) {
+	if !valid {
+		return
+	}
+	il.mux.Lock()
+	if !il.locked {
+		il.mux.Unlock()
+		return
+	}
+	il.locked = false
+	il.lastActivity = time.Now()
+	onUnlocked := il.OnUnlocked
+	il.mux.Unlock()
+
+	log.Print("Idle lock released")
+	il.lock.blockShortcuts(false)
+	if il.menu != nil {
+		il.window.SetMainMenu(il.menu)
+		il.menu = nil
+	}
+	c := il.window.Canvas()
+	c.Overlays().Remove(il.cover)
+	c.Unfocus()
+	if il.prevFocus != nil {
+		c.Focus(il.prevFocus)
+	}
+	il.prevFocus = nil
+	if onUnlocked != nil {
+		onUnlocked()
+	}
+}
+
+// keys typed while no widget has the focus count as activity, the
+// handlers already set on the canvas still get them
+func (il *IdleLocker) chainKeys() {
+	c := il.window.Canvas()
+	chain := keyHandlers{typedKey: c.OnTypedKey(), typedRune: c.OnTypedRune()}
+	dc, isDesktop := c.(desktop.Canvas)
+	if isDesktop {
+		chain.keyDown = dc.OnKeyDown()
+	}
+	il.keyChain = chain
+
+	c.SetOnTypedKey(func(e *fyne.KeyEvent) {
+		il.Poke()
+		if chain.typedKey != nil {
+			chain.typedKey(e)
+		}
+	})
+	c.SetOnTypedRune(func(r rune) {
+		il.Poke()
+		if chain.typedRune != nil {
+			chain.typedRune(r)
+		}
+	})
+	if isDesktop {
+		// modifiers and other keys that are never typed
+		dc.SetOnKeyDown(func(e *fyne.KeyEvent) {
+			il.Poke()
+			if chain.keyDown != nil {
+				chain.keyDown(e)
+			}
+		})
+	}
+}
+
+// puts the canvas handlers back as they were before Start()
+func (il *IdleLocker) unchainKeys() {
+	c := il.window.Canvas()
+	c.SetOnTypedKey(il.keyChain.typedKey)
+	c.SetOnTypedRune(il.keyChain.typedRune)
+	if dc, ok := c.(desktop.Canvas); ok {
+		dc.SetOnKeyDown(il.keyChain.keyDown)
+	}
+	il.keyChain = keyHandlers{}
+}
+
+func (s *activitySensor) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(color.Transparent))
+}
+
+// Implements desktop.Hoverable
+func (s *activitySensor) MouseIn(*desktop.MouseEvent) {
+	s.noticed()
+}
+
+// Implements desktop.Hoverable
+func (s *activitySensor) MouseMoved(*desktop.MouseEvent) {
+	s.noticed()
+}
+
+// Implements desktop.Hoverable
+func (s *activitySensor) MouseOut() {}
+
+// Implements mobile.Touchable
+func (s *activitySensor) TouchDown(*mobile.TouchEvent) {
+	s.noticed()
+}
+
+// Implements mobile.Touchable
+func (s *activitySensor) TouchUp(*mobile.TouchEvent) {}
+
+// Implements mobile.Touchable
+func (s *activitySensor) TouchCancel(*mobile.TouchEvent) {}
+
+// reports the activity and lets the next events reach the content
+func (s *activitySensor) noticed() {
+	s.onActivity()
+	s.Hide()
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// the idle checks are twice as frequent as the timeout, at least
+func checkInterval(timeout time.Duration) time.Duration {
+	return max(min(idleCHECK_INTERVAL, timeout/2), idleMIN_CHECK_INTERVAL)
+}
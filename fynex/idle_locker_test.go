@@ -0,0 +1,157 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the IdleLocker: its arguments, a timeout changed while it
+ * runs, when the pointer sensor is back on top and what stays
+ * disabled while the window is locked.
+ ********************************************************************/
+package fynex
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestNewIdleLockerArguments(t *testing.T) {
+	test.NewTempApp(t)
+	win := test.NewTempWindow(t, widget.NewLabel("content"))
+	pattern := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+	tests := []struct {
+		name    string
+		win     fyne.Window
+		pattern *PatternInfo
+		timeout time.Duration
+		wantErr bool
+	}{
+		{"valid", win, pattern, time.Minute, false},
+		{"nil pattern", win, nil, time.Minute, true},
+		{"empty pattern", win, &PatternInfo{}, time.Minute, true},
+		{"nil window", nil, pattern, time.Minute, true},
+		{"no timeout", win, pattern, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			il, err := NewIdleLocker(tt.win, tt.pattern, tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewIdleLocker() error = %v, want error %t", err, tt.wantErr)
+			}
+			if err == nil && il == nil {
+				t.Error("NewIdleLocker() returned neither a locker nor an error")
+			}
+		})
+	}
+}
+
+func TestIdleLockerSensor(t *testing.T) {
+	test.NewTempApp(t)
+	win := test.NewTempWindow(t, widget.NewLabel("content"))
+	il, err := NewIdleLocker(win, mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	il.Start()
+	defer il.Stop()
+	setTimes := func(activity, armed time.Duration) {
+		il.mux.Lock()
+		il.lastActivity, il.armedAt = time.Now().Add(-activity), time.Now().Add(-armed)
+		il.mux.Unlock()
+	}
+
+	// the pointer moved, the sensor steps aside and stays away a while
+	il.sensor.noticed()
+	il.check()
+	if il.sensor.Visible() {
+		t.Fatal("the sensor is back on top right after the activity")
+	}
+	// half the timeout later it watches again, the timeout restarts
+	setTimes(31*time.Minute, 2*time.Hour)
+	il.check()
+	if !il.sensor.Visible() || il.IsLocked() {
+		t.Fatalf("sensor visible %t, locked %t, want visible and unlocked", il.sensor.Visible(), il.IsLocked())
+	}
+	setTimes(2*time.Hour, 59*time.Minute)
+	il.check()
+	if il.IsLocked() {
+		t.Fatal("locked before the sensor watched for the whole timeout")
+	}
+	setTimes(2*time.Hour, 61*time.Minute)
+	il.check()
+	if !il.IsLocked() {
+		t.Fatal("not locked after the timeout")
+	}
+}
+
+func TestIdleLockerSetTimeout(t *testing.T) {
+	test.NewTempApp(t)
+	win := test.NewTempWindow(t, widget.NewLabel("content"))
+	il, err := NewIdleLocker(win, mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, timeout := range []time.Duration{0, -time.Minute} {
+		if err := il.SetTimeout(timeout); err == nil {
+			t.Errorf("SetTimeout(%s) was accepted", timeout)
+		}
+	}
+	if il.timeout != time.Hour {
+		t.Errorf("timeout %s after the refused ones, want 1h", il.timeout)
+	}
+
+	// the hour long timeout is checked every second, a shorter one
+	// is checked more often as soon as it is set
+	il.Start()
+	defer il.Stop()
+	if err := il.SetTimeout(40 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(idleCHECK_INTERVAL / 2)
+	for !il.IsLocked() {
+		if time.Now().After(deadline) {
+			t.Fatal("the shorter timeout did not lock before the next check of the old interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestIdleLockerDisablesWindow(t *testing.T) {
+	test.NewTempApp(t)
+	win := test.NewTempWindow(t, widget.NewLabel("content"))
+	menu := fyne.NewMainMenu(fyne.NewMenu("File", fyne.NewMenuItem("Open", func() {})))
+	win.SetMainMenu(menu)
+	il, err := NewIdleLocker(win, mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	il.Start()
+	defer il.Stop()
+
+	il.Lock()
+	if win.MainMenu() != nil {
+		t.Error("the main menu is still set while locked")
+	}
+	if win.Canvas().Focused() != il.PatternLock() {
+		t.Error("the PatternLock does not have the focus while locked")
+	}
+	// the focused PatternLock drops the shortcuts instead of passing
+	// them to the canvas
+	if !il.PatternLock().shortcutsBlocked {
+		t.Error("the shortcuts are not blocked while locked")
+	}
+
+	il.onValidated(true)
+	if win.MainMenu() != menu {
+		t.Error("the main menu was not restored")
+	}
+	if il.PatternLock().shortcutsBlocked {
+		t.Error("the shortcuts are still blocked after unlocking")
+	}
+}
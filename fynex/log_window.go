@@ -38,8 +38,14 @@ type logWriter struct {
 // to which the log output will be redirected. Call Show()
 // method before the main windows's ShowAndRun()
 func NewLogWindow(myApp fyne.App, width, height float32) fyne.Window {
+	return NewLogWindowWithTitle(myApp, Localize(MsgLogTitle), width, height)
+}
+
+// (ctor) like NewLogWindow() but with a custom window title instead of
+// the localized "System Logs".
+func NewLogWindowWithTitle(myApp fyne.App, title string, width, height float32) fyne.Window {
 	// 1. Create the Log Window (Non-modal)
-	logWindow := myApp.NewWindow("System Logs")
+	logWindow := myApp.NewWindow(title)
 	logWindow.Resize(fyne.NewSize(width, height))
 
 	// 2. Create Multi-line Entry for logs
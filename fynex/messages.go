@@ -0,0 +1,132 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Localizable user-facing messages of the fynex widgets and dialogs.
+ * The messages go through Fyne's lang package, the bundled Spanish
+ * and German translations are loaded at start-up and the MSG_*
+ * constants are the English fallback. Every widget can override any
+ * message per instance with a Messages map.
+ ********************************************************************/
+package fynex
+
+import (
+	"embed"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/lang"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const MSG_PATTERN_PROMPT = "Design your unlock pattern"
+const MSG_STRENGTH_WEAK = "Weak"
+const MSG_STRENGTH_FAIR = "Fair"
+const MSG_STRENGTH_GOOD = "Good"
+const MSG_STRENGTH_STRONG = "Strong"
+const MSG_LOG_TITLE = "System Logs"
+const MSG_ABOUT_TITLE = "About"
+const MSG_ABOUT_CLOSE = "Close"
+
+// the translation keys, namespaced so they never clash with those of
+// the application or of Fyne itself
+const (
+	MsgStatusUnblock        MessageID = "fynex.status.unblock"
+	MsgStatusDefine         MessageID = "fynex.status.define"
+	MsgStatusWrong          MessageID = "fynex.status.wrong"
+	MsgStatusGranted        MessageID = "fynex.status.granted"
+	MsgStatusLocked         MessageID = "fynex.status.locked" // %s is the remaining time
+	MsgStatusWeak           MessageID = "fynex.status.weak"
+	MsgStatusConfirm        MessageID = "fynex.status.confirm"
+	MsgStatusMismatch       MessageID = "fynex.status.mismatch"
+	MsgStatusInvalid        MessageID = "fynex.status.invalid"
+	MsgStatusCursor         MessageID = "fynex.status.cursor"          // %s is the dot
+	MsgStatusCursorSelected MessageID = "fynex.status.cursor.selected" // %s is the dot
+	MsgStatusCursorHidden   MessageID = "fynex.status.cursor.hidden"   // %d dots selected of %d
+	MsgStatusTyped          MessageID = "fynex.status.typed"           // %s is the masked notation
+	MsgStatusNotation       MessageID = "fynex.status.notation"        // %s is the error
+	MsgPatternPrompt        MessageID = "fynex.pattern.prompt"
+	MsgStrengthWeak         MessageID = "fynex.strength.weak"
+	MsgStrengthFair         MessageID = "fynex.strength.fair"
+	MsgStrengthGood         MessageID = "fynex.strength.good"
+	MsgStrengthStrong       MessageID = "fynex.strength.strong"
+	MsgLogTitle             MessageID = "fynex.log.title"
+	MsgAboutTitle           MessageID = "fynex.about.title"
+	MsgAboutClose           MessageID = "fynex.about.close"
+)
+
+// the English text of every message, used when there is no translation
+var messageFallbacks = map[MessageID]string{
+	MsgStatusUnblock:        MSG_STATUS_UNBLOCK,
+	MsgStatusDefine:         MSG_STATUS_DEFINE,
+	MsgStatusWrong:          MSG_STATUS_WRONG,
+	MsgStatusGranted:        MSG_STATUS_GRANTED,
+	MsgStatusLocked:         MSG_STATUS_LOCKED,
+	MsgStatusWeak:           MSG_STATUS_WEAK,
+	MsgStatusConfirm:        MSG_STATUS_CONFIRM,
+	MsgStatusMismatch:       MSG_STATUS_MISMATCH,
+	MsgStatusInvalid:        MSG_STATUS_INVALID,
+	MsgStatusCursor:         MSG_STATUS_CURSOR,
+	MsgStatusCursorSelected: MSG_STATUS_CURSOR_SELECTED,
+	MsgStatusCursorHidden:   MSG_STATUS_CURSOR_HIDDEN,
+	MsgStatusTyped:          MSG_STATUS_TYPED,
+	MsgStatusNotation:       MSG_STATUS_NOTATION,
+	MsgPatternPrompt:        MSG_PATTERN_PROMPT,
+	MsgStrengthWeak:         MSG_STRENGTH_WEAK,
+	MsgStrengthFair:         MSG_STRENGTH_FAIR,
+	MsgStrengthGood:         MSG_STRENGTH_GOOD,
+	MsgStrengthStrong:       MSG_STRENGTH_STRONG,
+	MsgLogTitle:             MSG_LOG_TITLE,
+	MsgAboutTitle:           MSG_ABOUT_TITLE,
+	MsgAboutClose:           MSG_ABOUT_CLOSE,
+}
+
+//go:embed translations
+var translations embed.FS
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// identifies a user-facing message, it is its translation key
+type MessageID string
+
+// Per-instance message overrides. A message that is not in the map is
+// translated to the current locale.
+type Messages map[MessageID]string
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// The overridden message, or else its translation. It can be called
+// on a nil map.
+func (m Messages) Get(id MessageID) string {
+	if text, found := m[id]; found {
+		return text
+	}
+	return Localize(id)
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// The translation of the message for the current locale, or its
+// English text. Messages with a %s verb are meant for fmt.Sprintf().
+func Localize(id MessageID) string {
+	fallback, found := messageFallbacks[id]
+	if !found {
+		fallback = string(id)
+	}
+	return lang.X(string(id), fallback)
+}
+
+// load the bundled translations into Fyne's translation system
+func init() {
+	if err := lang.AddTranslationsFS(translations, "translations"); err != nil {
+		fyne.LogError("Error loading the fynex translations", err)
+	}
+}
@@ -0,0 +1,133 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the localizable messages: the bundled translations are
+ * complete, they are picked by the locale, and the per-instance
+ * overrides of a PatternLock win over them.
+ ********************************************************************/
+package fynex
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"testing"
+
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+// the fmt verbs of a message, they must survive the translation
+var messageVERBS = regexp.MustCompile(`%[sd]`)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestTranslationBundles(t *testing.T) {
+	for _, name := range []string{"fynex.es.json", "fynex.de.json"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := translations.ReadFile("translations/" + name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var bundle map[MessageID]string
+			if err := json.Unmarshal(data, &bundle); err != nil {
+				t.Fatal(err)
+			}
+			for id, english := range messageFallbacks {
+				text, found := bundle[id]
+				if !found {
+					t.Errorf("%s is not translated", id)
+					continue
+				}
+				want := messageVERBS.FindAllString(english, -1)
+				if got := messageVERBS.FindAllString(text, -1); !slices.Equal(got, want) {
+					t.Errorf("%s has the verbs %v, want %v", id, got, want)
+				}
+			}
+			for id := range bundle {
+				if _, known := messageFallbacks[id]; !known {
+					t.Errorf("%s is not a message", id)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	tests := []struct {
+		locale string
+		id     MessageID
+		want   string
+	}{
+		{"en_US.UTF-8", MsgStatusUnblock, MSG_STATUS_UNBLOCK},
+		{"es_ES.UTF-8", MsgStatusUnblock, "Dibuje el patrón para desbloquear"},
+		{"es_MX.UTF-8", MsgStatusWrong, "Patrón incorrecto. Inténtelo de nuevo."},
+		{"de_DE.UTF-8", MsgStatusUnblock, "Muster zum Entsperren zeichnen"},
+		{"de_AT.UTF-8", MsgStatusGranted, "Zugriff gewährt!"},
+		// no fynex translation, the English text
+		{"ja_JP.UTF-8", MsgStatusWrong, MSG_STATUS_WRONG},
+		{"es_ES.UTF-8", MessageID("fynex.unknown"), "fynex.unknown"},
+	}
+	// after the variables are restored, the system locale again
+	t.Cleanup(reloadTranslations)
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			t.Setenv("LANGUAGE", "")
+			t.Setenv("LC_ALL", tt.locale)
+			reloadTranslations()
+			if got := Localize(tt.id); got != tt.want {
+				t.Errorf("Localize(%s) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageOverrides(t *testing.T) {
+	test.NewTempApp(t)
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	p.SetValidPattern(mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3))
+
+	p.SetMessages(Messages{MsgStatusUnblock: "Swipe to open", MsgStatusWrong: "Nope"})
+	p.Reset()
+	if p.Status != "Swipe to open" {
+		t.Errorf("status %q, want the override", p.Status)
+	}
+	p.SetMessage(MsgStatusWrong, "Try again")
+	tests := []struct {
+		id   MessageID
+		want string
+	}{
+		{MsgStatusWrong, "Try again"},
+		{MsgStatusGranted, Localize(MsgStatusGranted)},
+	}
+	for _, tt := range tests {
+		if got := p.message(tt.id); got != tt.want {
+			t.Errorf("message(%s) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+
+	// the map is copied, changing it later has no effect
+	overrides := Messages{MsgStatusUnblock: "Swipe to open"}
+	p.SetMessages(overrides)
+	overrides[MsgStatusUnblock] = "Changed"
+	if got := p.message(MsgStatusUnblock); got != "Swipe to open" {
+		t.Errorf("message(%s) = %q after changing the map", MsgStatusUnblock, got)
+	}
+	p.SetMessages(nil)
+	p.Reset()
+	if want := Localize(MsgStatusUnblock); p.Status != want {
+		t.Errorf("status %q without overrides, want %q", p.Status, want)
+	}
+}
+
+// loads the bundled translations again, which picks the locale anew
+func reloadTranslations() {
+	lang.AddTranslationsFS(translations, "translations")
+}
@@ -0,0 +1,268 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Animations of the PatternLock widget: the playback of a pattern
+ * dot by dot (tutorials, "show my pattern") and the feedback after
+ * validation, a red shake when wrong or a green pulse when granted.
+ * Both end by fading the path out.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// time to go from one dot to the next when Play() gets no speed
+	DefaultPlaybackSpeed = 400 * time.Millisecond
+
+	// duration of the shake, pulse or hold before fading out
+	effectDURATION = 600 * time.Millisecond
+	// duration of the fade out of the drawn path
+	fadeDURATION = 400 * time.Millisecond
+	// the largest horizontal displacement of the shake (pixels)
+	shakeAMPLITUDE = 12
+	// the extra size of the selected dots at the top of the pulse
+	pulseGROWTH = 0.5
+)
+
+const (
+	effectHold  pathEffectKind = iota // show the path as is
+	effectShake                       // shake horizontally
+	effectPulse                       // grow and shrink the selected dots
+)
+
+var (
+	// the path as drawn, without any animation effect
+	noPathEffect = pathEffect{scale: 1, alpha: 1}
+
+	// Play() was given a pattern for another grid
+	ErrPlaybackMode = errors.New("pattern mode differs from the widget grid")
+	// animations need a running fyne application
+	ErrNoAnimation = errors.New("no application to run the animation")
+)
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// which animation is applied to the drawn path before fading it
+type pathEffectKind uint8
+
+// the animation parameters used by the renderer to draw the path
+type pathEffect struct {
+	color  color.Color // replaces the line color, nil keeps it
+	offset float32     // horizontal displacement of the path (shake)
+	scale  float32     // size factor of the selected dots (pulse)
+	alpha  float32     // opacity of the path, 1 is fully visible
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// Animates the drawing of the pattern dot by dot, taking speed to go
+// from one dot to the next (DefaultPlaybackSpeed if zero). The path
+// fades out at the end. Playback is only a display, nothing is
+// validated and no callback is called. Any input stops it.
+func (p *PatternLock) Play(pi *PatternInfo, speed time.Duration) error {
+	if pi == nil || pi.Mode() != p.gridMode {
+		return fmt.Errorf("%w: %v", ErrPlaybackMode, pi)
+	}
+	if fyne.CurrentApp() == nil {
+		return ErrNoAnimation
+	}
+	if speed <= 0 {
+		speed = DefaultPlaybackSpeed
+	}
+	p.onMain(func() {
+		p.play(pi, speed)
+	})
+	return nil
+}
+
+// whether a pattern is being played back with Play()
+func (p *PatternLock) IsPlaying() bool {
+	return p.playing
+}
+
+// Stops the playback or the validation feedback and clears the grid
+func (p *PatternLock) StopAnimation() {
+	p.onMain(p.clearDrawing)
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// starts the playback animation (main goroutine)
+func (p *PatternLock) play(pi *PatternInfo, speed time.Duration) {
+	p.stopAnimation()
+	sequence := pi.Pattern()
+	segments := len(sequence) - 1
+	log.Printf("Playing a pattern of %d dots", len(sequence))
+
+	var anim *fyne.Animation
+	anim = fyne.NewAnimation(speed*time.Duration(segments), func(progress float32) {
+		if p.animation != anim {
+			return
+		}
+		p.playbackFrame(sequence, progress)
+		if progress >= 1 {
+			p.playing = false
+			p.animatePath(nil, effectHold)
+			return
+		}
+		p.Refresh()
+	})
+	anim.Curve = fyne.AnimationLinear
+	p.animation = anim
+	p.playing = true
+	p.effect = noPathEffect
+	p.Sequence = []int{}
+	anim.Start()
+}
+
+// Shows the playback of the sequence at progress 0..1: the dots reached
+// so far and the drag line heading to the next one (main goroutine).
+func (p *PatternLock) playbackFrame(sequence []int, progress float32) {
+	segments := len(sequence) - 1
+	position := progress * float32(segments)
+	reached := min(int(position), segments)
+	p.Sequence = append([]int{}, sequence[:reached+1]...)
+	p.active = reached < segments
+	if p.active {
+		// the drag line heads to the next dot
+		from, to := p.DotCenter(sequence[reached]), p.DotCenter(sequence[reached+1])
+		fraction := position - float32(reached)
+		p.hover = fyne.NewPos(from.X+(to.X-from.X)*fraction, from.Y+(to.Y-from.Y)*fraction)
+	}
+}
+
+// Animates the validation result on the drawn path: a red shake when
+// wrong or a green pulse when granted, followed by the fade out.
+func (p *PatternLock) animateResult(isValid bool) {
+	style := p.Style()
+	if isValid {
+		p.animatePath(style.GrantedColor, effectPulse)
+	} else {
+		p.animatePath(style.WrongColor, effectShake)
+	}
+}
+
+// Applies the effect to the current sequence in the given color (nil
+// keeps the line color) and then fades it out. The sequence is cleared
+// when done. Without a running application it is cleared right away.
+func (p *PatternLock) animatePath(clr color.Color, kind pathEffectKind) {
+	p.stopEffect()
+	if fyne.CurrentApp() == nil || len(p.Sequence) == 0 {
+		p.clearPath()
+		p.Refresh()
+		return
+	}
+
+	total := effectDURATION + fadeDURATION
+	effectEnd := float32(effectDURATION) / float32(total)
+
+	var anim *fyne.Animation
+	anim = fyne.NewAnimation(total, func(progress float32) {
+		if p.animation != anim {
+			return
+		}
+		effect := noPathEffect
+		effect.color = clr
+		if progress < effectEnd {
+			phase := float64(progress / effectEnd)
+			switch kind {
+			case effectShake:
+				effect.offset = float32(math.Sin(phase*6*math.Pi) * shakeAMPLITUDE * (1 - phase))
+			case effectPulse:
+				effect.scale = 1 + float32(math.Sin(phase*math.Pi)*pulseGROWTH)
+			}
+		} else {
+			effect.alpha = 1 - (progress-effectEnd)/(1-effectEnd)
+		}
+		p.effect = effect
+		if progress >= 1 {
+			p.clearPath()
+		}
+		p.Refresh()
+	})
+	anim.Curve = fyne.AnimationLinear
+	p.animation = anim
+	p.active = false
+	anim.Start()
+}
+
+// whether the user is drawing, a playback only looks like it
+func (p *PatternLock) isDrawing() bool {
+	return p.active && !p.playing
+}
+
+// stops any running animation and clears the animated path
+func (p *PatternLock) stopAnimation() {
+	if p.animation == nil {
+		return
+	}
+	p.stopEffect()
+	p.clearPath()
+}
+
+// stops any running animation but keeps the current path
+func (p *PatternLock) stopEffect() {
+	if p.animation != nil {
+		p.animation.Stop()
+		p.animation = nil
+	}
+	p.playing = false
+	p.effect = noPathEffect
+}
+
+// removes the path from the grid once the animation is over
+func (p *PatternLock) clearPath() {
+	p.animation = nil
+	p.playing = false
+	p.effect = noPathEffect
+	p.active = false
+	p.Sequence = []int{}
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// the color with its opacity multiplied by alpha (0..1)
+func fadeColor(c color.Color, alpha float32) color.Color {
+	if alpha >= 1 {
+		return c
+	}
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	nrgba.A = uint8(float32(nrgba.A) * max(alpha, 0))
+	return nrgba
+}
+
+// the color between from (alpha 0) and to (alpha 1)
+func blendColor(from, to color.Color, alpha float32) color.Color {
+	if alpha >= 1 {
+		return to
+	}
+	alpha = max(alpha, 0)
+	a := color.NRGBAModel.Convert(from).(color.NRGBA)
+	b := color.NRGBAModel.Convert(to).(color.NRGBA)
+	mix := func(x, y uint8) uint8 {
+		return uint8(float32(x) + (float32(y)-float32(x))*alpha)
+	}
+	return color.NRGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+}
@@ -0,0 +1,139 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the PatternLock playback: its arguments, the frames drawn
+ * as it progresses and its cancellation by any input.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestPlayArguments(t *testing.T) {
+	app := test.NewTempApp(t)
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	if err := p.Play(nil, 0); !errors.Is(err, ErrPlaybackMode) {
+		t.Errorf("Play(nil) error = %v, want ErrPlaybackMode", err)
+	}
+	other := mustPattern(t, []int{0, 1, 2, 3}, PatternMode4x4)
+	if err := p.Play(other, 0); !errors.Is(err, ErrPlaybackMode) {
+		t.Errorf("Play() of a 4x4 pattern error = %v, want ErrPlaybackMode", err)
+	}
+
+	fyne.SetCurrentApp(nil)
+	err := p.Play(mustPattern(t, []int{0, 1, 2}, PatternMode3x3), 0)
+	fyne.SetCurrentApp(app)
+	if !errors.Is(err, ErrNoAnimation) {
+		t.Errorf("Play() without an application error = %v, want ErrNoAnimation", err)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	test.NewTempApp(t)
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	w := test.NewTempWindow(t, p)
+	w.Resize(fyne.NewSize(300, 360))
+	called := 0
+	p.OnComplete = func([]int) { called++ }
+	p.OnStart = func() { called++ }
+	p.OnValidated = func(bool) { called++ }
+
+	// the test driver runs an animation to its end at once
+	if err := p.Play(mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if p.IsPlaying() || len(p.Sequence) != 0 {
+		t.Errorf("playing %t with %v after the end of the playback", p.IsPlaying(), p.Sequence)
+	}
+	if called != 0 {
+		t.Errorf("the playback called %d callbacks", called)
+	}
+}
+
+func TestPlaybackFrames(t *testing.T) {
+	test.NewTempApp(t)
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	w := test.NewTempWindow(t, p)
+	w.Resize(fyne.NewSize(300, 360))
+	sequence := []int{0, 1, 2, 5, 8}
+	midway := func(from, to int) fyne.Position {
+		a, b := p.DotCenter(from), p.DotCenter(to)
+		return fyne.NewPos((a.X+b.X)/2, (a.Y+b.Y)/2)
+	}
+
+	tests := []struct {
+		progress  float32
+		want      []int
+		wantHover fyne.Position // where the drag line ends, unless done
+	}{
+		{0, []int{0}, p.DotCenter(0)},
+		{0.125, []int{0}, midway(0, 1)},
+		{0.5, []int{0, 1, 2}, p.DotCenter(2)},
+		{0.625, []int{0, 1, 2}, midway(2, 5)},
+		{1, sequence, fyne.Position{}},
+	}
+	for _, tt := range tests {
+		p.playbackFrame(sequence, tt.progress)
+		if !slices.Equal(p.Sequence, tt.want) {
+			t.Errorf("at %g the path is %v, want %v", tt.progress, p.Sequence, tt.want)
+		}
+		done := tt.progress >= 1
+		if p.active == done {
+			t.Errorf("at %g the drag line is shown %t", tt.progress, p.active)
+		}
+		if off := p.hover.Subtract(tt.wantHover); !done && off.X*off.X+off.Y*off.Y > 0.01 {
+			t.Errorf("at %g the drag line ends at %v, want %v", tt.progress, p.hover, tt.wantHover)
+		}
+	}
+}
+
+func TestPlaybackCancelled(t *testing.T) {
+	test.NewTempApp(t)
+	tests := []struct {
+		name  string
+		input func(p *PatternLock)
+		want  []int // the path left
+	}{
+		{"drag", func(p *PatternLock) {
+			p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: p.DotCenter(8)}})
+		}, []int{8}},
+		{"tap", func(p *PatternLock) {
+			p.Tapped(&fyne.PointEvent{Position: p.DotCenter(6)})
+		}, []int{6}},
+		{"space", func(p *PatternLock) { p.TypedKey(&fyne.KeyEvent{Name: fyne.KeySpace}) }, []int{0}},
+		{"typed", func(p *PatternLock) { p.TypedRune('A') }, []int{}},
+		{"stop", func(p *PatternLock) { p.StopAnimation() }, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPatternLockFor(PatternMode3x3, nil)
+			w := test.NewTempWindow(t, p)
+			w.Resize(fyne.NewSize(300, 360))
+
+			// a playback halfway through
+			p.animation = fyne.NewAnimation(time.Hour, func(float32) {})
+			p.playing = true
+			p.playbackFrame([]int{2, 4, 3, 5}, 0.5)
+
+			tt.input(p)
+			if p.IsPlaying() || p.animation != nil {
+				t.Error("the playback goes on")
+			}
+			if !slices.Equal(p.Sequence, tt.want) {
+				t.Errorf("the path is %v, want %v", p.Sequence, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Enrollment of a new pattern on the PatternLock widget. The user
+ * draws the new pattern and then confirms it by drawing it again.
+ * Only when both drawings match is the pattern accepted.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const MSG_STATUS_CONFIRM = "Draw the pattern again to confirm"
+const MSG_STATUS_MISMATCH = "Patterns don't match. Draw NEW Pattern"
+const MSG_STATUS_INVALID = "Invalid pattern. Draw NEW Pattern"
+
+const (
+	designDraw    designStep = iota // waiting for the new pattern
+	designConfirm                   // waiting for the confirmation drawing
+)
+
+var (
+	// the confirmation drawing differs from the first one
+	ErrPatternMismatch = errors.New("patterns don't match")
+	// the strength is below the minimum set with SetStrengthMeter()
+	ErrPatternTooWeak = errors.New("pattern too weak")
+	// CancelDesign() was called
+	ErrDesignCancelled = errors.New("pattern design cancelled")
+)
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// the step of the enrollment state machine
+type designStep uint8
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// whether the widget is enrolling a new pattern
+func (p *PatternLock) IsDesigning() bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.designing
+}
+
+// Abandon the enrollment of a new pattern. The pattern that was valid
+// before EnterDesignState() is restored and OnDefineFailed is called
+// with ErrDesignCancelled.
+func (p *PatternLock) CancelDesign() {
+	p.mux.Lock()
+	if !p.designing {
+		p.mux.Unlock()
+		return
+	}
+	p.descriptor = p.prevDescriptor
+	p.hash = p.prevHash
+	p.secretsVersion++
+	p.leaveDesignState()
+	onDefineFailed := p.OnDefineFailed
+	p.mux.Unlock()
+
+	log.Print("Design cancelled")
+	p.onMain(p.clearDrawing)
+	if onDefineFailed != nil {
+		onDefineFailed(ErrDesignCancelled)
+	}
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// Advances the enrollment state machine with the drawn sequence:
+// draw -> confirm -> accepted, or back to draw on any failure.
+func (p *PatternLock) onDesigning() {
+	mode := p.gridMode
+	sequence := append([]int{}, p.Sequence...)
+
+	p.mux.Lock()
+	step, candidate := p.designStep, p.candidate
+	checkStrength, minStrength := p.strengthMeter, p.minStrength
+	rules := p.rules
+	onComplete, onDefined, onDefineFailed := p.OnComplete, p.OnDefined, p.OnDefineFailed
+	p.mux.Unlock()
+
+	switch step {
+	case designDraw:
+		candidate, err := NewPatternWithRules(sequence, mode, rules)
+		if err == nil && checkStrength {
+			if strength := candidate.Strength(); strength.Level < minStrength {
+				err = fmt.Errorf("%w: %s", ErrPatternTooWeak, strength)
+			}
+		}
+		if err != nil {
+			log.Print("Refused new pattern: ", err)
+			if errors.Is(err, ErrPatternTooWeak) {
+				p.SetStatus(p.message(MsgStatusWeak))
+			} else {
+				p.SetStatus(p.message(MsgStatusInvalid))
+			}
+			if onDefineFailed != nil {
+				onDefineFailed(err)
+			}
+			return
+		}
+		p.mux.Lock()
+		p.candidate = candidate
+		p.designStep = designConfirm
+		p.showStatus(p.messages.Get(MsgStatusConfirm))
+		p.mux.Unlock()
+		p.refreshUI()
+
+	case designConfirm:
+		p.mux.Lock()
+		p.candidate = nil
+		matched := candidate != nil && reflect.DeepEqual(sequence, candidate.Pattern())
+		if matched {
+			p.descriptor = candidate
+			p.secretsVersion++
+			p.leaveDesignState()
+		} else {
+			p.designStep = designDraw
+			p.showStatus(p.messages.Get(MsgStatusMismatch))
+		}
+		p.mux.Unlock()
+		p.refreshUI()
+
+		if !matched {
+			log.Print("Confirmation mismatch")
+			if onDefineFailed != nil {
+				onDefineFailed(ErrPatternMismatch)
+			}
+			return
+		}
+		log.Printf("New pattern defined: %d dots", len(sequence))
+
+		if onComplete != nil {
+			onComplete(sequence)
+		}
+		if onDefined != nil {
+			onDefined(candidate)
+		}
+	}
+}
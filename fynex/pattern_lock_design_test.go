@@ -0,0 +1,135 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the design state of the PatternLock: draw, confirm, accept,
+ * and back to draw on a refused pattern, a mismatch or a cancel.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestDesignStates(t *testing.T) {
+	previous := mustPattern(t, []int{0, 4, 8}, PatternMode3x3)
+	newPattern := []int{0, 1, 2, 5, 8}
+	var completed [][]int
+	var defined []*PatternInfo
+	var failed []error
+	p := designLock(t, previous, &completed, &defined, &failed)
+
+	// wantErr is nil when OnDefineFailed must not be called
+	step := func(name string, sequence []int, wantStep designStep, wantStatus MessageID, wantErr func(error) bool) {
+		t.Helper()
+		failed = failed[:0]
+		drawSequence(p, sequence)
+		p.mux.Lock()
+		gotStep, designing, status := p.designStep, p.designing, p.Status
+		p.mux.Unlock()
+		if !designing || gotStep != wantStep {
+			t.Errorf("%s: designing %t at step %d, want step %d", name, designing, gotStep, wantStep)
+		}
+		if want := p.message(wantStatus); status != want {
+			t.Errorf("%s: status %q, want %q", name, status, want)
+		}
+		if (wantErr == nil) != (len(failed) == 0) || (wantErr != nil && !wantErr(failed[0])) {
+			t.Errorf("%s: OnDefineFailed() got %v", name, failed)
+		}
+	}
+
+	refused := func(err error) bool {
+		var ruleErr *PatternRuleError
+		return errors.As(err, &ruleErr)
+	}
+	mismatch := func(err error) bool { return errors.Is(err, ErrPatternMismatch) }
+
+	p.EnterDesignState()
+	step("refused", []int{0, 1}, designDraw, MsgStatusInvalid, refused)
+	step("first drawing", newPattern, designConfirm, MsgStatusConfirm, nil)
+	step("mismatch", []int{0, 1, 2, 5}, designDraw, MsgStatusMismatch, mismatch)
+	step("first drawing again", newPattern, designConfirm, MsgStatusConfirm, nil)
+	if len(completed) != 0 || len(defined) != 0 {
+		t.Fatalf("OnComplete() %v and OnDefined() %v before the confirmation", completed, defined)
+	}
+
+	// confirmed
+	drawSequence(p, newPattern)
+	if p.IsDesigning() {
+		t.Error("still designing after the confirmation")
+	}
+	if len(completed) != 1 || !slices.Equal(completed[0], newPattern) {
+		t.Errorf("OnComplete() got %v, want %v once", completed, newPattern)
+	}
+	if len(defined) != 1 || !slices.Equal(defined[0].Pattern(), newPattern) {
+		t.Errorf("OnDefined() got %v, want %v once", defined, newPattern)
+	}
+	p.mux.Lock()
+	valid := p.descriptor
+	p.mux.Unlock()
+	if valid != defined[0] {
+		t.Error("the confirmed pattern is not the valid pattern")
+	}
+}
+
+func TestDesignCancel(t *testing.T) {
+	previous := mustPattern(t, []int{0, 4, 8}, PatternMode3x3)
+	var completed [][]int
+	var defined []*PatternInfo
+	var failed []error
+	p := designLock(t, previous, &completed, &defined, &failed)
+
+	p.EnterDesignState()
+	drawSequence(p, []int{0, 1, 2, 5, 8})
+	p.CancelDesign()
+	if p.IsDesigning() {
+		t.Error("still designing after CancelDesign()")
+	}
+	if len(failed) != 1 || !errors.Is(failed[0], ErrDesignCancelled) {
+		t.Errorf("OnDefineFailed() got %v, want ErrDesignCancelled", failed)
+	}
+	p.mux.Lock()
+	valid, candidate := p.descriptor, p.candidate
+	p.mux.Unlock()
+	if valid != previous || candidate != nil {
+		t.Error("the previous pattern was not restored")
+	}
+	if len(completed) != 0 || len(defined) != 0 {
+		t.Errorf("OnComplete() %v and OnDefined() %v after a cancel", completed, defined)
+	}
+
+	// cancelling outside of design state does nothing
+	p.CancelDesign()
+	if len(failed) != 1 {
+		t.Errorf("OnDefineFailed() called %d times, want once", len(failed))
+	}
+}
+
+// a rendered 3x3 widget that records its design callbacks
+func designLock(t *testing.T, valid *PatternInfo, completed *[][]int, defined *[]*PatternInfo, failed *[]error) *PatternLock {
+	test.NewTempApp(t)
+	p := NewPatternLockWith(valid, nil)
+	p.OnComplete = func(sequence []int) { *completed = append(*completed, sequence) }
+	p.OnDefined = func(pi *PatternInfo) { *defined = append(*defined, pi) }
+	p.OnDefineFailed = func(err error) { *failed = append(*failed, err) }
+	w := test.NewTempWindow(t, p)
+	w.Resize(fyne.NewSize(300, 360))
+	return p
+}
+
+// drags over the dots of the sequence and releases
+func drawSequence(p *PatternLock, sequence []int) {
+	for _, dot := range sequence {
+		p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: p.DotCenter(dot)}})
+	}
+	p.DragEnd()
+}
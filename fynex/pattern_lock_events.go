@@ -0,0 +1,181 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Interaction events of the PatternLock widget. A drawing session
+ * goes from the first touch (or key) to the release, it is reported
+ * step by step and recorded in a timing trace with the instant each
+ * dot was reached, e.g. for UX analytics or rhythm checks.
+ ********************************************************************/
+package fynex
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// the pattern was released with dots and submitted
+	TraceCompleted TraceOutcome = iota
+	// the pattern was released without any dot
+	TraceCancelled
+	// the drawing was cleared before it was released
+	TraceReset
+)
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// How a drawing session ended
+type TraceOutcome uint8
+
+// A dot of a drawing session and the instant it was reached
+type TracedDot struct {
+	Index    int           // internal index of the dot
+	Position fyne.Position // center of the dot in the widget
+	At       time.Time
+}
+
+// The timing trace of a drawing session
+type PatternTrace struct {
+	Mode    PatternMode
+	Started time.Time
+	Ended   time.Time
+	Dots    []TracedDot
+	Outcome TraceOutcome
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// implements fmt.Stringer
+func (to TraceOutcome) String() string {
+	var result string
+	switch to {
+	case TraceCompleted:
+		result = "Completed"
+	case TraceCancelled:
+		result = "Cancelled"
+	case TraceReset:
+		result = "Reset"
+	default:
+		result = ""
+	}
+	return result
+}
+
+// the dots of the session in the order they were reached
+func (pt *PatternTrace) Sequence() []int {
+	sequence := make([]int, len(pt.Dots))
+	for i, dot := range pt.Dots {
+		sequence[i] = dot.Index
+	}
+	return sequence
+}
+
+// The time taken from each dot to the next one, the drawing rhythm.
+// Dots added in pass-through mode take no time.
+func (pt *PatternTrace) Intervals() []time.Duration {
+	if len(pt.Dots) < 2 {
+		return []time.Duration{}
+	}
+	intervals := make([]time.Duration, len(pt.Dots)-1)
+	for i := 1; i < len(pt.Dots); i++ {
+		intervals[i-1] = pt.Dots[i].At.Sub(pt.Dots[i-1].At)
+	}
+	return intervals
+}
+
+// the time from the start of the session to its end
+func (pt *PatternTrace) Duration() time.Duration {
+	return pt.Ended.Sub(pt.Started)
+}
+
+// Clears the drawing in progress and the typed notation, OnReset is
+// called if there was one. It can be called from any goroutine.
+func (p *PatternLock) Reset() {
+	p.onMain(func() {
+		p.typed = ""
+		p.clearDrawing()
+		p.SetStatus(p.idleStatus())
+	})
+}
+
+// The trace of the last drawing session that ended, nil before the
+// first one. It is already set when OnComplete or OnValidated run.
+func (p *PatternLock) LastTrace() *PatternTrace {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.lastTrace
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// begins a drawing session unless one is in progress (main goroutine)
+func (p *PatternLock) beginSession() {
+	if p.session != nil {
+		return
+	}
+	p.session = &PatternTrace{Mode: p.gridMode, Started: time.Now(), Dots: []TracedDot{}}
+
+	p.mux.Lock()
+	onStart := p.OnStart
+	p.mux.Unlock()
+	if onStart != nil {
+		onStart()
+	}
+}
+
+// records a dot added to the drawing (main goroutine)
+func (p *PatternLock) traceDot(index int) {
+	p.beginSession()
+	position := p.DotCenter(index)
+	p.session.Dots = append(p.session.Dots, TracedDot{Index: index, Position: position, At: time.Now()})
+
+	p.mux.Lock()
+	onDotAdded := p.OnDotAdded
+	p.mux.Unlock()
+	if onDotAdded != nil {
+		onDotAdded(index, position)
+	}
+}
+
+// ends the drawing session, if any, and reports it (main goroutine)
+func (p *PatternLock) endSession(outcome TraceOutcome) {
+	trace := p.session
+	if trace == nil {
+		return
+	}
+	p.session = nil
+	trace.Ended = time.Now()
+	trace.Outcome = outcome
+
+	p.mux.Lock()
+	p.lastTrace = trace
+	onTrace, onCancel, onReset := p.OnTrace, p.OnCancel, p.OnReset
+	p.mux.Unlock()
+
+	if onTrace != nil {
+		onTrace(trace)
+	}
+	switch outcome {
+	case TraceCancelled:
+		if onCancel != nil {
+			onCancel()
+		}
+	case TraceReset:
+		if onReset != nil {
+			onReset()
+		}
+	}
+}
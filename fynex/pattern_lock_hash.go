@@ -0,0 +1,230 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Salted key-derivation hash of an unlock pattern. It lets the
+ * application persist and validate a pattern without ever storing
+ * the plaintext dot sequence.
+ ********************************************************************/
+package fynex
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// identifies the algorithm in the PatternHash string form
+	hashALGORITHM = "pbkdf2-sha256"
+	// the size of the derived key in bytes
+	hashKEY_LENGTH = sha256.Size
+	// the lowest iteration count we accept, anything less is pointless
+	hashMIN_ITERATIONS = 1000
+	// The highest iteration count we accept, 50 times the default. A
+	// tampered hash could otherwise make every verification run forever.
+	hashMAX_ITERATIONS = 10000000
+	// the shortest salt we accept (in bytes)
+	hashMIN_SALT_LENGTH = 8
+)
+
+// The default key-derivation parameters. A pattern has very little
+// entropy so the iteration count is what makes brute-forcing costly.
+// Lower it on slow devices, raise it when you can afford it.
+var DefaultHashParams = HashParams{
+	Iterations: 200000,
+	SaltLength: 16,
+}
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// Tunable parameters of the pattern key-derivation function
+type HashParams struct {
+	Iterations int // PBKDF2 rounds (1000 to 10000000)
+	SaltLength int // random salt size in bytes (minimum 8)
+}
+
+// A salted PBKDF2-HMAC-SHA256 hash of an unlock pattern. The pattern
+// mode is part of the hashed material, so the same dots on a different
+// grid do not verify.
+type PatternHash struct {
+	mode       PatternMode
+	iterations int
+	salt       []byte
+	key        []byte
+}
+
+/* -----------------------------------------------------------------
+ *                  C O N S T R U C T O R S
+ * -----------------------------------------------------------------*/
+
+// derive a salted hash from a pattern descriptor using the given
+// key-derivation parameters and a fresh random salt.
+func HashPattern(pi *PatternInfo, params HashParams) (*PatternHash, error) {
+	if pi == nil {
+		return nil, errors.New("cannot hash a nil pattern")
+	}
+	if params.Iterations < hashMIN_ITERATIONS || params.Iterations > hashMAX_ITERATIONS {
+		return nil, fmt.Errorf("hash needs %d to %d iterations", hashMIN_ITERATIONS, hashMAX_ITERATIONS)
+	}
+	if params.SaltLength < hashMIN_SALT_LENGTH {
+		return nil, fmt.Errorf("hash salt needs at least %d bytes", hashMIN_SALT_LENGTH)
+	}
+
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return &PatternHash{
+		mode:       pi.mode,
+		iterations: params.Iterations,
+		salt:       salt,
+		key:        derivePatternKey(pi.mode, pi.pattern, salt, params.Iterations),
+	}, nil
+}
+
+// parse a hash in the format produced by PatternHash.String(), that is
+// "pbkdf2-sha256$MODE$ITERATIONS$SALT$KEY" with salt and key in
+// unpadded base64.
+func ParsePatternHash(s string) (*PatternHash, error) {
+	parts := strings.Split(strings.TrimSpace(s), "$")
+	if len(parts) != 5 {
+		return nil, errors.New("malformed pattern hash")
+	}
+	if parts[0] != hashALGORITHM {
+		return nil, fmt.Errorf("unsupported pattern hash algorithm '%s'", parts[0])
+	}
+
+	mode, err := ParsePatternMode(parts[1])
+	if err != nil || !mode.IsValid() {
+		return nil, fmt.Errorf("invalid pattern hash mode '%s'", parts[1])
+	}
+	iterations, err := strconv.Atoi(parts[2])
+	if err != nil || iterations < hashMIN_ITERATIONS || iterations > hashMAX_ITERATIONS {
+		return nil, fmt.Errorf("invalid pattern hash iterations '%s'", parts[2])
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(salt) < hashMIN_SALT_LENGTH {
+		return nil, errors.New("invalid pattern hash salt")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(key) != hashKEY_LENGTH {
+		return nil, errors.New("invalid pattern hash key")
+	}
+
+	return &PatternHash{
+		mode:       mode,
+		iterations: iterations,
+		salt:       salt,
+		key:        key,
+	}, nil
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// derive a salted hash of this pattern using DefaultHashParams
+func (pi *PatternInfo) Hash() (*PatternHash, error) {
+	return HashPattern(pi, DefaultHashParams)
+}
+
+// the pattern mode the hash was derived for
+func (ph *PatternHash) Mode() PatternMode {
+	return ph.mode
+}
+
+// The width or height size of the matrix for the hashed pattern
+func (ph *PatternHash) Size() int {
+	return ph.mode.Width()
+}
+
+// the number of key-derivation rounds
+func (ph *PatternHash) Iterations() int {
+	return ph.iterations
+}
+
+// implements fmt.Stringer with the storable form of the hash
+func (ph *PatternHash) String() string {
+	return strings.Join([]string{
+		hashALGORITHM,
+		ph.mode.String(),
+		strconv.Itoa(ph.iterations),
+		base64.RawStdEncoding.EncodeToString(ph.salt),
+		base64.RawStdEncoding.EncodeToString(ph.key),
+	}, "$")
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// checks whether a drawn sequence (internal format) matches the hashed
+// pattern. The key comparison is done in constant time.
+func VerifyPattern(hash *PatternHash, sequence []int) bool {
+	if hash == nil || len(sequence) == 0 {
+		return false
+	}
+	if validateIndices(sequence, hash.mode) != nil {
+		return false
+	}
+
+	key := derivePatternKey(hash.mode, sequence, hash.salt, hash.iterations)
+	return subtle.ConstantTimeCompare(key, hash.key) == 1
+}
+
+// the password material fed to the KDF: the mode followed by one byte
+// per dot index.
+func derivePatternKey(mode PatternMode, sequence []int, salt []byte, iterations int) []byte {
+	secret := make([]byte, 0, len(sequence)+1)
+	secret = append(secret, byte(mode))
+	for _, index := range sequence {
+		secret = append(secret, byte(index))
+	}
+	return pbkdf2SHA256(secret, salt, iterations, hashKEY_LENGTH)
+}
+
+// PBKDF2 (RFC 8018) with HMAC-SHA256 as pseudo-random function.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	numBlocks := (keyLen + hashLen - 1) / hashLen
+
+	var counter [4]byte
+	derived := make([]byte, 0, numBlocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= numBlocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		derived = prf.Sum(derived)
+		t := derived[len(derived)-hashLen:]
+		copy(u, t)
+
+		for n := 2; n <= iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+
+	return derived[:keyLen]
+}
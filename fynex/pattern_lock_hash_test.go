@@ -0,0 +1,160 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the salted pattern hash: parameters, verification and the
+ * storable string form.
+ ********************************************************************/
+package fynex
+
+import (
+	"strings"
+	"testing"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+// the cheapest parameters that are accepted, fast enough for tests
+var testHashParams = HashParams{Iterations: hashMIN_ITERATIONS, SaltLength: hashMIN_SALT_LENGTH}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestHashPatternParams(t *testing.T) {
+	pattern := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+	tests := []struct {
+		name    string
+		pattern *PatternInfo
+		params  HashParams
+		wantErr bool
+	}{
+		{"defaults", pattern, DefaultHashParams, false},
+		{"minimum", pattern, testHashParams, false},
+		{"nil pattern", nil, testHashParams, true},
+		{"few iterations", pattern, HashParams{Iterations: hashMIN_ITERATIONS - 1, SaltLength: 16}, true},
+		{"too many iterations", pattern, HashParams{Iterations: hashMAX_ITERATIONS + 1, SaltLength: 16}, true},
+		{"short salt", pattern, HashParams{Iterations: hashMIN_ITERATIONS, SaltLength: hashMIN_SALT_LENGTH - 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := HashPattern(tt.pattern, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HashPattern() error = %v, want error %t", err, tt.wantErr)
+			}
+			if err == nil && hash.Iterations() != tt.params.Iterations {
+				t.Errorf("Iterations() = %d, want %d", hash.Iterations(), tt.params.Iterations)
+			}
+		})
+	}
+}
+
+func TestVerifyPattern(t *testing.T) {
+	pattern := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+	hash, err := HashPattern(pattern, testHashParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		hash     *PatternHash
+		sequence []int
+		want     bool
+	}{
+		{"same dots", hash, []int{0, 1, 2, 5, 8}, true},
+		{"reversed", hash, []int{8, 5, 2, 1, 0}, false},
+		{"prefix", hash, []int{0, 1, 2, 5}, false},
+		{"one more dot", hash, []int{0, 1, 2, 5, 8, 7}, false},
+		{"empty", hash, []int{}, false},
+		{"off the grid", hash, []int{0, 1, 2, 5, 9}, false},
+		{"nil hash", nil, []int{0, 1, 2, 5, 8}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPattern(tt.hash, tt.sequence); got != tt.want {
+				t.Errorf("VerifyPattern(%v) = %t, want %t", tt.sequence, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashSaltAndMode(t *testing.T) {
+	sequence := []int{0, 1, 2, 5, 8}
+	first, _ := HashPattern(mustPattern(t, sequence, PatternMode3x3), testHashParams)
+	second, _ := HashPattern(mustPattern(t, sequence, PatternMode3x3), testHashParams)
+	if first.String() == second.String() {
+		t.Error("two hashes of the same pattern share their salt")
+	}
+	if !VerifyPattern(second, sequence) {
+		t.Error("a fresh salt does not verify")
+	}
+
+	// the same dots on a 4x4 grid are another pattern
+	other, _ := HashPattern(mustPattern(t, []int{0, 1, 2, 6, 10}, PatternMode4x4), testHashParams)
+	if VerifyPattern(other, sequence) {
+		t.Error("a 4x4 hash verifies a 3x3 pattern")
+	}
+}
+
+func TestParsePatternHash(t *testing.T) {
+	hash, err := HashPattern(mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), testHashParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := hash.String()
+
+	parsed, err := ParsePatternHash(stored)
+	if err != nil {
+		t.Fatalf("ParsePatternHash(%q) error = %v", stored, err)
+	}
+	if parsed.String() != stored || parsed.Mode() != PatternMode3x3 {
+		t.Errorf("round trip = %q, want %q", parsed.String(), stored)
+	}
+	if !VerifyPattern(parsed, []int{0, 1, 2, 5, 8}) {
+		t.Error("the parsed hash does not verify")
+	}
+
+	parts := strings.Split(stored, "$")
+	replace := func(at int, value string) string {
+		changed := append([]string{}, parts...)
+		changed[at] = value
+		return strings.Join(changed, "$")
+	}
+	malformed := []struct {
+		name string
+		hash string
+	}{
+		{"empty", ""},
+		{"missing part", strings.Join(parts[:4], "$")},
+		{"algorithm", replace(0, "bcrypt")},
+		{"mode", replace(1, "2x2")},
+		{"no mode", replace(1, "None")},
+		{"mode text", replace(1, "grid")},
+		{"iterations", replace(2, "999")},
+		{"too many iterations", replace(2, "10000001")},
+		{"huge iterations", replace(2, "9223372036854775807")},
+		{"not a number", replace(2, "many")},
+		{"short salt", replace(3, "c2FsdA")},
+		{"salt encoding", replace(3, "!!!!!!!!!!!!")},
+		{"short key", replace(4, "a2V5")},
+	}
+	for _, tt := range malformed {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePatternHash(tt.hash); err == nil {
+				t.Errorf("ParsePatternHash(%q) accepted a malformed hash", tt.hash)
+			}
+		})
+	}
+}
+
+// the pattern, the test fails if it is not valid
+func mustPattern(t *testing.T, sequence []int, mode PatternMode) *PatternInfo {
+	t.Helper()
+	pattern, err := NewPattern(sequence, mode)
+	if err != nil {
+		t.Fatalf("NewPattern(%v, %s) error = %v", sequence, mode, err)
+	}
+	return pattern
+}
@@ -0,0 +1,102 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Pointer feedback of the PatternLock widget on the desktop. A ring
+ * marks the dot under the mouse pointer and its hit area, and while
+ * a pattern is drawn the dots the pointer approaches grow a little.
+ ********************************************************************/
+package fynex
+
+import (
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// how far a dot grows when the pointer is about to hit it
+	proximityGROWTH = 0.5
+	// the distance, in hit radiuses, from which an approached dot grows
+	proximityREACH = 2.5
+	// stroke width of the hover ring
+	hoverRING_WIDTH = 2
+)
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// implements desktop.Hoverable
+func (p *PatternLock) MouseIn(e *desktop.MouseEvent) {
+	p.setHoverDot(p.dotAt(e.Position))
+}
+
+// implements desktop.Hoverable
+func (p *PatternLock) MouseMoved(e *desktop.MouseEvent) {
+	p.setHoverDot(p.dotAt(e.Position))
+}
+
+// implements desktop.Hoverable
+func (p *PatternLock) MouseOut() {
+	p.setHoverDot(-1)
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// The dot within the hit radius of the position, -1 if none. It uses
+// the same geometry as the renderer, whatever the status placement.
+func (p *PatternLock) dotAt(pos fyne.Position) int {
+	p.mux.Lock()
+	ratio := p.style.HitRadiusRatio
+	p.mux.Unlock()
+	if ratio <= 0 {
+		ratio = DefaultHitRadiusRatio
+	}
+
+	geometry := p.geometry(p.Size())
+	id := geometry.cellAt(pos)
+	if id < 0 || distance(pos, geometry.dotCenter(id)) >= geometry.hitRadius(ratio) {
+		return -1
+	}
+	return id
+}
+
+// moves the hover ring, the widget is only refreshed when the dot changed
+func (p *PatternLock) setHoverDot(id int) {
+	p.mux.Lock()
+	changed := p.hoverDot != id
+	p.hoverDot = id
+	p.mux.Unlock()
+
+	if changed {
+		p.Refresh()
+	}
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// the distance between two positions
+func distance(a, b fyne.Position) float32 {
+	return float32(math.Hypot(float64(a.X-b.X), float64(a.Y-b.Y)))
+}
+
+// The scale of a dot that the pointer approaches while drawing, 1 when
+// it is beyond reach and up to 1+proximityGROWTH at its center.
+func proximityScale(center, pointer fyne.Position, hitRadius float32) float32 {
+	reach := hitRadius * proximityREACH
+	dist := distance(center, pointer)
+	if reach <= 0 || dist >= reach {
+		return 1
+	}
+	return 1 + proximityGROWTH*(1-dist/reach)
+}
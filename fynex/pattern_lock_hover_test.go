@@ -0,0 +1,147 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the pointer feedback of the PatternLock: the edge of the
+ * configurable hit area, the hover ring and the growth of the dots
+ * that the pointer approaches.
+ ********************************************************************/
+package fynex
+
+import (
+	"math"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestDotAtHitRadius(t *testing.T) {
+	test.NewTempApp(t)
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	w := test.NewTempWindow(t, p)
+	w.Resize(fyne.NewSize(300, 360))
+	cell := p.geometry(p.Size()).cell
+	side := fyne.Min(cell.Width, cell.Height)
+
+	tests := []struct {
+		name   string
+		ratio  float32 // of the style, 0 for the default
+		radius float32 // the hit radius it gives
+	}{
+		{"default", 0, side / 3},
+		{"small", 0.2, side * 0.2},
+		{"large", 0.45, side * 0.45},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p.SetStyle(PatternLockStyle{HitRadiusRatio: tt.ratio})
+			center := p.DotCenter(4)
+			points := []struct {
+				pos  fyne.Position
+				want int
+			}{
+				{center, 4},
+				{center.AddXY(tt.radius-0.5, 0), 4},
+				{center.AddXY(0, -(tt.radius - 0.5)), 4},
+				{center.AddXY(tt.radius+0.5, 0), -1},
+				{center.AddXY(-(tt.radius + 0.5), 0), -1},
+				// the corner of the square is beyond the circle
+				{center.AddXY(tt.radius*0.8, tt.radius*0.8), -1},
+			}
+			for _, pt := range points {
+				if got := p.dotAt(pt.pos); got != pt.want {
+					t.Errorf("dotAt(%v) = %d, want %d (hit radius %g)", pt.pos.Subtract(center), got, pt.want, tt.radius)
+				}
+			}
+		})
+	}
+	// a larger ratio never reaches into the next cell
+	p.SetStyle(PatternLockStyle{HitRadiusRatio: 0.9})
+	center := p.DotCenter(4)
+	if got := p.dotAt(center.AddXY(side/2-0.5, 0)); got != 4 {
+		t.Errorf("dotAt() at the edge of the cell = %d, want 4", got)
+	}
+	if got := p.dotAt(center.AddXY(side/2+0.5, 0)); got != 5 {
+		t.Errorf("dotAt() past the edge of the cell = %d, want the next dot 5", got)
+	}
+	if got := p.dotAt(fyne.NewPos(-5, -5)); got != -1 {
+		t.Errorf("dotAt() outside the widget = %d", got)
+	}
+}
+
+func TestHoverRing(t *testing.T) {
+	test.NewTempApp(t)
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	w := test.NewTempWindow(t, p)
+	w.Resize(fyne.NewSize(300, 360))
+	r := test.TempWidgetRenderer(t, p).(*patternRenderer)
+	hitRadius := p.geometry(p.Size()).hitRadius(DefaultHitRadiusRatio)
+	mouse := func(pos fyne.Position) *desktop.MouseEvent {
+		return &desktop.MouseEvent{PointEvent: fyne.PointEvent{Position: pos}}
+	}
+	hovered := func() int {
+		p.mux.Lock()
+		defer p.mux.Unlock()
+		return p.hoverDot
+	}
+
+	p.MouseIn(mouse(p.DotCenter(2).AddXY(3, 3)))
+	r.Refresh()
+	if hovered() != 2 || !r.hoverRing.Visible() {
+		t.Fatalf("hovering dot %d with the ring visible %t, want dot 2 and a ring", hovered(), r.hoverRing.Visible())
+	}
+	if want := p.DotCenter(2).SubtractXY(hitRadius, hitRadius); r.hoverRing.Position() != want {
+		t.Errorf("ring at %v, want %v", r.hoverRing.Position(), want)
+	}
+	if size := r.hoverRing.Size(); math.Abs(float64(size.Width-2*hitRadius)) > 0.01 || math.Abs(float64(size.Height-2*hitRadius)) > 0.01 {
+		t.Errorf("ring of %v, want the hit area of radius %g", size, hitRadius)
+	}
+
+	// between the dots there is no ring
+	p.MouseMoved(mouse(p.DotCenter(2).AddXY(hitRadius+1, 0)))
+	r.Refresh()
+	if hovered() != -1 || r.hoverRing.Visible() {
+		t.Errorf("hovering dot %d off the hit area", hovered())
+	}
+	p.MouseMoved(mouse(p.DotCenter(7)))
+	p.MouseOut()
+	r.Refresh()
+	if hovered() != -1 || r.hoverRing.Visible() {
+		t.Error("the ring stayed after the mouse left")
+	}
+
+	// while drawing the path shows where the pointer is
+	p.MouseIn(mouse(p.DotCenter(4)))
+	p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: p.DotCenter(4)}})
+	r.Refresh()
+	if r.hoverRing.Visible() {
+		t.Error("the ring is shown while drawing")
+	}
+}
+
+func TestProximityScale(t *testing.T) {
+	center := fyne.NewPos(100, 100)
+	tests := []struct {
+		name    string
+		pointer fyne.Position
+		radius  float32
+		want    float32
+	}{
+		{"on the dot", center, 10, 1 + proximityGROWTH},
+		{"halfway", center.AddXY(12.5, 0), 10, 1 + proximityGROWTH/2},
+		{"at the reach", center.AddXY(0, 25), 10, 1},
+		{"beyond", center.AddXY(30, 30), 10, 1},
+		{"no hit area", center, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := proximityScale(center, tt.pointer, tt.radius); got != tt.want {
+			t.Errorf("%s: proximityScale() = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Several identities on one PatternLock, e.g. a shared terminal where
+ * every user has a pattern, and an optional duress pattern that looks
+ * like a success to anyone watching but raises a silent alarm.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+var (
+	// the pattern is already the valid one, the duress one or belongs to
+	// another identity
+	ErrPatternInUse = errors.New("pattern already in use")
+	// an identity needs a user ID
+	ErrNoUserID = errors.New("identity without user ID")
+)
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// a secret pattern, in plaintext or as a salted hash
+type patternSecret struct {
+	userID     string // empty for the duress pattern
+	descriptor *PatternInfo
+	hash       *PatternHash
+}
+
+// The secrets of a widget at one time. Comparing a hash runs PBKDF2, so
+// new secrets are compared with a copy and not under the lock.
+type patternSecrets struct {
+	valid      patternSecret
+	identities []patternSecret
+	duress     *patternSecret
+	version    uint64 // of the widget secrets when copied
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// Enrolls the pattern of a user. A drawing that matches it is granted
+// and OnIdentified gets the user ID. Enrolling a user again replaces
+// its pattern. It fails for another grid, or with ErrPatternInUse if
+// the pattern is the valid one, belongs to another identity or is the
+// duress pattern.
+func (p *PatternLock) EnrollIdentity(userID string, pinfo *PatternInfo) error {
+	if pinfo == nil {
+		return errors.New("cannot enroll a nil pattern")
+	}
+	return p.enroll(patternSecret{userID: userID, descriptor: pinfo})
+}
+
+// Like EnrollIdentity() with a salted hash, the plaintext pattern is
+// never held. Duplicates of other hashes cannot be detected.
+func (p *PatternLock) EnrollIdentityHash(userID string, hash *PatternHash) error {
+	if hash == nil {
+		return errors.New("cannot enroll a nil pattern hash")
+	}
+	return p.enroll(patternSecret{userID: userID, hash: hash})
+}
+
+// Removes the identity of the user, if enrolled
+func (p *PatternLock) RemoveIdentity(userID string) *PatternLock {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.identities = slices.DeleteFunc(p.identities, func(s patternSecret) bool {
+		return s.userID == userID
+	})
+	return p
+}
+
+// the user IDs enrolled, in the order of enrollment
+func (p *PatternLock) Identities() []string {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	users := make([]string, len(p.identities))
+	for i, identity := range p.identities {
+		users[i] = identity.userID
+	}
+	return users
+}
+
+// Sets the duress pattern, nil removes it. When it is drawn the widget
+// shows "Access Granted!" and OnValidated gets true, just like for the
+// valid pattern, but OnDuress is called instead of OnIdentified. It
+// fails with ErrPatternInUse for the valid pattern or an identity.
+func (p *PatternLock) SetDuressPattern(pinfo *PatternInfo) error {
+	if pinfo == nil {
+		p.mux.Lock()
+		p.duress = nil
+		p.mux.Unlock()
+		return nil
+	}
+	return p.setDuress(patternSecret{descriptor: pinfo})
+}
+
+// Like SetDuressPattern() with a salted hash, nil removes it
+func (p *PatternLock) SetDuressHash(hash *PatternHash) error {
+	if hash == nil {
+		p.mux.Lock()
+		p.duress = nil
+		p.mux.Unlock()
+		return nil
+	}
+	return p.setDuress(patternSecret{hash: hash})
+}
+
+// whether the sequence is this secret
+func (s patternSecret) matches(sequence []int) bool {
+	if s.hash != nil {
+		return VerifyPattern(s.hash, sequence)
+	}
+	return s.descriptor != nil && reflect.DeepEqual(sequence, s.descriptor.Pattern())
+}
+
+// Whether both secrets are the same pattern of another user. Only
+// plaintext patterns can be compared, with each other or with a hash.
+func (s patternSecret) clashesWith(other patternSecret) bool {
+	if s.userID != "" && s.userID == other.userID {
+		// a replacement
+		return false
+	}
+	switch {
+	case s.descriptor != nil:
+		return other.matches(s.descriptor.Pattern())
+	case other.descriptor != nil:
+		return s.matches(other.descriptor.Pattern())
+	}
+	return false
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// adds or replaces the identity
+func (p *PatternLock) enroll(identity patternSecret) error {
+	if identity.userID == "" {
+		return ErrNoUserID
+	}
+	if err := p.checkSecret(identity); err != nil {
+		return err
+	}
+
+	return p.commitSecret(func(secrets *patternSecrets) bool {
+		return identity.clashesWith(secrets.valid) ||
+			slices.ContainsFunc(secrets.identities, identity.clashesWith) ||
+			(secrets.duress != nil && identity.clashesWith(*secrets.duress))
+	}, func() {
+		if at := slices.IndexFunc(p.identities, func(s patternSecret) bool {
+			return s.userID == identity.userID
+		}); at >= 0 {
+			p.identities[at] = identity
+		} else {
+			p.identities = append(p.identities, identity)
+		}
+	})
+}
+
+// Sets the duress pattern unless it is the valid pattern or belongs to
+// an identity, the valid pattern would win and the alarm never go off.
+func (p *PatternLock) setDuress(duress patternSecret) error {
+	if err := p.checkSecret(duress); err != nil {
+		return err
+	}
+
+	return p.commitSecret(func(secrets *patternSecrets) bool {
+		return duress.clashesWith(secrets.valid) ||
+			slices.ContainsFunc(secrets.identities, duress.clashesWith)
+	}, func() {
+		p.duress = &duress
+	})
+}
+
+// Sets the valid pattern or hash unless it is the duress pattern or
+// belongs to an identity. Its grid is not checked, it never was.
+func (p *PatternLock) setValid(valid patternSecret) error {
+	return p.commitSecret(func(secrets *patternSecrets) bool {
+		return slices.ContainsFunc(secrets.identities, valid.clashesWith) ||
+			(secrets.duress != nil && valid.clashesWith(*secrets.duress))
+	}, func() {
+		p.descriptor, p.hash = valid.descriptor, valid.hash
+	})
+}
+
+// Commits a new secret unless it clashes with the others. The clash is
+// looked for in a copy of the secrets, without the lock; if they changed
+// meanwhile it is looked for again.
+func (p *PatternLock) commitSecret(clashes func(*patternSecrets) bool, commit func()) error {
+	for {
+		p.mux.Lock()
+		secrets := p.secretsLocked()
+		p.mux.Unlock()
+
+		if clashes(&secrets) {
+			return ErrPatternInUse
+		}
+
+		p.mux.Lock()
+		if p.secretsVersion == secrets.version {
+			commit()
+			p.secretsVersion++
+			p.mux.Unlock()
+			return nil
+		}
+		p.mux.Unlock()
+	}
+}
+
+// a copy of the secrets, the caller holds the lock
+func (p *PatternLock) secretsLocked() patternSecrets {
+	return patternSecrets{
+		valid:      patternSecret{descriptor: p.descriptor, hash: p.hash},
+		identities: slices.Clone(p.identities),
+		duress:     p.duress,
+		version:    p.secretsVersion,
+	}
+}
+
+// the secret must be for the grid of the widget
+func (p *PatternLock) checkSecret(s patternSecret) error {
+	var mode PatternMode
+	if s.hash != nil {
+		mode = s.hash.Mode()
+	} else {
+		mode = s.descriptor.Mode()
+	}
+	if mode != p.gridMode {
+		return fmt.Errorf("pattern for %s but the grid is %s", mode, p.gridMode)
+	}
+	return nil
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// the identity or the duress secret matched by the sequence, nil if none
+func matchSecret(identities []patternSecret, duress *patternSecret, sequence []int) *patternSecret {
+	for i := range identities {
+		if identities[i].matches(sequence) {
+			return &identities[i]
+		}
+	}
+	if duress != nil && duress.matches(sequence) {
+		return duress
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the identities and the duress pattern: which secrets clash
+ * with the valid pattern and with each other, and that they are not
+ * compared under the lock.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestSetDuressClashes(t *testing.T) {
+	valid := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+	other := mustPattern(t, []int{6, 7, 8, 5, 2}, PatternMode3x3)
+	validHash, _ := HashPattern(valid, testHashParams)
+	otherHash, _ := HashPattern(other, testHashParams)
+
+	tests := []struct {
+		name    string
+		setup   func(p *PatternLock)
+		duress  patternSecret
+		wantErr error
+	}{
+		{"other pattern", func(p *PatternLock) { p.SetValidPattern(valid) },
+			patternSecret{descriptor: other}, nil},
+		{"valid pattern", func(p *PatternLock) { p.SetValidPattern(valid) },
+			patternSecret{descriptor: valid}, ErrPatternInUse},
+		{"hash of the valid pattern", func(p *PatternLock) { p.SetValidPattern(valid) },
+			patternSecret{hash: validHash}, ErrPatternInUse},
+		{"valid hash", func(p *PatternLock) { p.SetValidHash(validHash) },
+			patternSecret{descriptor: valid}, ErrPatternInUse},
+		{"other than the valid hash", func(p *PatternLock) { p.SetValidHash(validHash) },
+			patternSecret{descriptor: other}, nil},
+		// two hashes cannot be compared
+		{"two hashes", func(p *PatternLock) { p.SetValidHash(validHash) },
+			patternSecret{hash: otherHash}, nil},
+		{"identity", func(p *PatternLock) { p.EnrollIdentity("alice", other) },
+			patternSecret{descriptor: other}, ErrPatternInUse},
+		{"no valid pattern", func(p *PatternLock) {},
+			patternSecret{descriptor: valid}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPatternLockFor(PatternMode3x3, nil)
+			tt.setup(p)
+			if err := p.setDuress(tt.duress); !errors.Is(err, tt.wantErr) {
+				t.Errorf("setDuress() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnrollClashes(t *testing.T) {
+	valid := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+	other := mustPattern(t, []int{6, 7, 8, 5, 2}, PatternMode3x3)
+	validHash, _ := HashPattern(valid, testHashParams)
+
+	tests := []struct {
+		name    string
+		setup   func(p *PatternLock)
+		pattern *PatternInfo
+		wantErr error
+	}{
+		{"other pattern", func(p *PatternLock) { p.SetValidPattern(valid) }, other, nil},
+		{"valid pattern", func(p *PatternLock) { p.SetValidPattern(valid) }, valid, ErrPatternInUse},
+		{"valid hash", func(p *PatternLock) { p.SetValidHash(validHash) }, valid, ErrPatternInUse},
+		{"duress", func(p *PatternLock) { p.SetDuressPattern(other) }, other, ErrPatternInUse},
+		{"another user", func(p *PatternLock) { p.EnrollIdentity("bob", other) }, other, ErrPatternInUse},
+		{"replaced", func(p *PatternLock) { p.EnrollIdentity("alice", other) }, other, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPatternLockFor(PatternMode3x3, nil)
+			tt.setup(p)
+			if err := p.EnrollIdentity("alice", tt.pattern); !errors.Is(err, tt.wantErr) {
+				t.Errorf("EnrollIdentity() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetValidClashes(t *testing.T) {
+	previous := mustPattern(t, []int{0, 4, 8}, PatternMode3x3)
+	alice := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+	duress := mustPattern(t, []int{6, 7, 8, 5, 2}, PatternMode3x3)
+	duressHash, _ := HashPattern(duress, testHashParams)
+
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	p.SetValidPattern(previous)
+	if err := p.EnrollIdentity("alice", alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetDuressPattern(duress); err != nil {
+		t.Fatal(err)
+	}
+	validPattern := func() *PatternInfo {
+		p.mux.Lock()
+		defer p.mux.Unlock()
+		return p.descriptor
+	}
+
+	for _, refused := range []*PatternInfo{alice, duress} {
+		if p.SetValidPattern(refused); validPattern() != previous {
+			t.Errorf("SetValidPattern(%s) replaced the valid pattern", refused)
+		}
+	}
+	if p.SetValidHash(duressHash); validPattern() != previous {
+		t.Error("SetValidHash() of the duress pattern replaced the valid pattern")
+	}
+	other := mustPattern(t, []int{2, 4, 6}, PatternMode3x3)
+	if p.SetValidPattern(other); validPattern() != other {
+		t.Error("SetValidPattern() refused a pattern in no use")
+	}
+
+	store := NewPreferencesPatternStore(test.NewTempApp(t).Preferences(), "lock.users")
+	if err := SavePattern(store, "carol", duress); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetValidStoreEntry(store, "carol"); !errors.Is(err, ErrPatternInUse) {
+		t.Errorf("SetValidStoreEntry() of the duress pattern error = %v, want ErrPatternInUse", err)
+	}
+}
+
+func TestSecretsComparedUnlocked(t *testing.T) {
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	bob := mustPattern(t, []int{6, 7, 8, 5, 2}, PatternMode3x3)
+
+	calls := 0
+	err := p.commitSecret(func(secrets *patternSecrets) bool {
+		calls++
+		if !p.mux.TryLock() {
+			t.Fatal("the secrets are compared under the lock")
+		}
+		p.mux.Unlock()
+		if calls == 1 {
+			// changed meanwhile, the copy is stale
+			p.EnrollIdentity("bob", bob)
+		}
+		return slices.ContainsFunc(secrets.identities, func(s patternSecret) bool {
+			return s.userID == "bob"
+		})
+	}, func() {
+		t.Error("committed although bob was enrolled meanwhile")
+	})
+	if calls != 2 || !errors.Is(err, ErrPatternInUse) {
+		t.Errorf("compared %d times, error = %v, want twice and ErrPatternInUse", calls, err)
+	}
+}
+
+func TestMatchSecret(t *testing.T) {
+	alice := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+	bobHash, _ := HashPattern(mustPattern(t, []int{6, 7, 8, 5, 2}, PatternMode3x3), testHashParams)
+	identities := []patternSecret{{userID: "alice", descriptor: alice}, {userID: "bob", hash: bobHash}}
+	duress := &patternSecret{descriptor: mustPattern(t, []int{0, 4, 8}, PatternMode3x3)}
+
+	tests := []struct {
+		sequence []int
+		want     string // the user, "duress" or "" for none
+	}{
+		{[]int{0, 1, 2, 5, 8}, "alice"},
+		{[]int{6, 7, 8, 5, 2}, "bob"},
+		{[]int{0, 4, 8}, "duress"},
+		{[]int{8, 4, 0}, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if matched := matchSecret(identities, duress, tt.sequence); matched != nil {
+			got = matched.userID
+			if got == "" {
+				got = "duress"
+			}
+		}
+		if got != tt.want {
+			t.Errorf("matchSecret(%v) = %q, want %q", tt.sequence, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Renders a PatternInfo to an image: the grid, the path with arrows,
+ * the start and end markers and optionally the order of the dots.
+ * It is rasterized in memory, no window or GPU is needed, so it can
+ * make thumbnails, previews and the documentation samples.
+ ********************************************************************/
+package fynex
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+// the segments used to approximate a circle
+const circleSEGMENTS = 32
+
+var (
+	imageBackground = color.NRGBA{R: 0x20, G: 0x20, B: 0x24, A: 0xff}
+	imageDotColor   = color.NRGBA{R: 150, G: 150, B: 150, A: 0xff}
+	imagePathColor  = color.NRGBA{R: 0, G: 200, B: 255, A: 0xff}
+	imageStartColor = color.NRGBA{R: 0, G: 200, B: 0, A: 0xff}
+	imageEndColor   = color.NRGBA{R: 220, G: 0, B: 0, A: 0xff}
+	imageTextColor  = color.NRGBA{R: 0, G: 0, B: 0, A: 0xff}
+)
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// How RenderPattern draws a pattern. A zero field takes its default,
+// so PatternImageOptions{} gives a dark thumbnail with a light-blue
+// path, a green start and a red end.
+type PatternImageOptions struct {
+	Background color.Color // fills the image, use color.Transparent for none
+	DotColor   color.Color // dots that are not part of the pattern
+	PathColor  color.Color // lines, arrows and visited dots
+	StartColor color.Color // ring around the first dot
+	EndColor   color.Color // ring around the last dot
+	TextColor  color.Color // the order numbers on the dots
+	Numbered   bool        // write the order of each visited dot on it
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// draws filled shapes on an image
+type patternCanvas struct {
+	dst    *image.NRGBA
+	raster *vector.Rasterizer
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// the options with every zero field set to its default
+func (o PatternImageOptions) withDefaults() PatternImageOptions {
+	if o.Background == nil {
+		o.Background = imageBackground
+	}
+	if o.DotColor == nil {
+		o.DotColor = imageDotColor
+	}
+	if o.PathColor == nil {
+		o.PathColor = imagePathColor
+	}
+	if o.StartColor == nil {
+		o.StartColor = imageStartColor
+	}
+	if o.EndColor == nil {
+		o.EndColor = imageEndColor
+	}
+	if o.TextColor == nil {
+		o.TextColor = imageTextColor
+	}
+	return o
+}
+
+// fills the closed polygon
+func (c *patternCanvas) fill(clr color.Color, points ...[2]float32) {
+	c.raster.Reset(c.dst.Bounds().Dx(), c.dst.Bounds().Dy())
+	c.path(points)
+	c.raster.Draw(c.dst, c.dst.Bounds(), image.NewUniform(clr), image.Point{})
+}
+
+// adds a closed polygon to the rasterizer
+func (c *patternCanvas) path(points [][2]float32) {
+	c.raster.MoveTo(points[0][0], points[0][1])
+	for _, pt := range points[1:] {
+		c.raster.LineTo(pt[0], pt[1])
+	}
+	c.raster.ClosePath()
+}
+
+// fills a circle
+func (c *patternCanvas) circle(clr color.Color, x, y, radius float32) {
+	c.fill(clr, circlePoints(x, y, radius, false)...)
+}
+
+// draws a ring between the two radiuses
+func (c *patternCanvas) ring(clr color.Color, x, y, outer, inner float32) {
+	c.raster.Reset(c.dst.Bounds().Dx(), c.dst.Bounds().Dy())
+	c.path(circlePoints(x, y, outer, false))
+	// the opposite winding cuts the hole
+	c.path(circlePoints(x, y, inner, true))
+	c.raster.Draw(c.dst, c.dst.Bounds(), image.NewUniform(clr), image.Point{})
+}
+
+// draws a line of the given width with square ends
+func (c *patternCanvas) line(clr color.Color, x1, y1, x2, y2, width float32) {
+	dx, dy := x2-x1, y2-y1
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return
+	}
+	// half the width along the normal
+	nx, ny := -dy/length*width/2, dx/length*width/2
+	c.fill(clr, [2]float32{x1 + nx, y1 + ny}, [2]float32{x2 + nx, y2 + ny},
+		[2]float32{x2 - nx, y2 - ny}, [2]float32{x1 - nx, y1 - ny})
+}
+
+// draws an arrowhead centered at the point, heading along dx,dy
+func (c *patternCanvas) arrow(clr color.Color, x, y, dx, dy, size float32) {
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return
+	}
+	ux, uy := dx/length*size, dy/length*size
+	c.fill(clr, [2]float32{x + ux, y + uy},
+		[2]float32{x - ux - uy*0.8, y - uy + ux*0.8},
+		[2]float32{x - ux + uy*0.8, y - uy - ux*0.8})
+}
+
+// writes the text centered at the point
+func (c *patternCanvas) text(clr color.Color, x, y float32, text string) {
+	face := basicfont.Face7x13
+	drawer := &font.Drawer{Dst: c.dst, Src: image.NewUniform(clr), Face: face}
+	width := drawer.MeasureString(text)
+	metrics := face.Metrics()
+	drawer.Dot = fixed.Point26_6{
+		X: fixed.Int26_6(x*64) - width/2,
+		Y: fixed.Int26_6(y*64) + (metrics.Ascent-metrics.Descent)/2,
+	}
+	drawer.DrawString(text)
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// Draws the pattern on an image of the given size in pixels. The grid
+// has square cells and is centered. The path has an arrow on every
+// segment, the first dot has a ring in StartColor and the last one in
+// EndColor.
+func RenderPattern(pi *PatternInfo, width, height int, opts PatternImageOptions) (image.Image, error) {
+	if pi == nil || !pi.Mode().IsValid() {
+		return nil, fmt.Errorf("cannot render pattern %v", pi)
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid image size %dx%d", width, height)
+	}
+	opts = opts.withDefaults()
+
+	c := &patternCanvas{
+		dst:    image.NewNRGBA(image.Rect(0, 0, width, height)),
+		raster: vector.NewRasterizer(width, height),
+	}
+	draw.Draw(c.dst, c.dst.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+
+	columns, rows := pi.Columns(), pi.Rows()
+	cell := min(float32(width)/float32(columns), float32(height)/float32(rows))
+	left := (float32(width) - cell*float32(columns)) / 2
+	top := (float32(height) - cell*float32(rows)) / 2
+	center := func(index int) (float32, float32) {
+		return left + cell*(float32(index%columns)+0.5), top + cell*(float32(index/columns)+0.5)
+	}
+	dotRadius := cell / 10
+	if opts.Numbered {
+		// room for the number
+		dotRadius = max(cell/7, 8)
+	}
+
+	// the idle grid
+	for i := 0; i < pi.Mode().Dots(); i++ {
+		x, y := center(i)
+		c.circle(opts.DotColor, x, y, cell/10)
+	}
+
+	sequence := pi.Pattern()
+	if len(sequence) == 0 {
+		return c.dst, nil
+	}
+
+	// the path with an arrow halfway on every segment
+	for i := 1; i < len(sequence); i++ {
+		x1, y1 := center(sequence[i-1])
+		x2, y2 := center(sequence[i])
+		c.line(opts.PathColor, x1, y1, x2, y2, cell/16)
+		c.arrow(opts.PathColor, (x1+x2)/2, (y1+y2)/2, x2-x1, y2-y1, cell/10)
+	}
+
+	// start and end markers
+	x, y := center(sequence[0])
+	c.ring(opts.StartColor, x, y, dotRadius*1.9, dotRadius*1.45)
+	x, y = center(sequence[len(sequence)-1])
+	c.ring(opts.EndColor, x, y, dotRadius*1.9, dotRadius*1.45)
+
+	// the visited dots, on top of the lines
+	for order, dot := range sequence {
+		x, y := center(dot)
+		c.circle(opts.PathColor, x, y, dotRadius)
+		if opts.Numbered {
+			c.text(opts.TextColor, x, y, strconv.Itoa(order+1))
+		}
+	}
+
+	return c.dst, nil
+}
+
+// Like RenderPattern but encoded as a PNG resource, e.g. to show it in
+// a canvas.Image. The resource is named after the pattern mode.
+func RenderPatternPNG(pi *PatternInfo, width, height int, opts PatternImageOptions) (fyne.Resource, error) {
+	img, err := RenderPattern(pi, width, height, opts)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("pattern_%s.png", pi.Mode())
+	return fyne.NewStaticResource(name, buf.Bytes()), nil
+}
+
+// the corners of a polygon approximating the circle, clockwise on
+// screen unless reversed
+func circlePoints(x, y, radius float32, reverse bool) [][2]float32 {
+	points := make([][2]float32, circleSEGMENTS)
+	for i := range points {
+		angle := 2 * math.Pi * float64(i) / circleSEGMENTS
+		if reverse {
+			angle = -angle
+		}
+		points[i] = [2]float32{x + radius*float32(math.Cos(angle)), y + radius*float32(math.Sin(angle))}
+	}
+	return points
+}
@@ -0,0 +1,140 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the pattern images: the arguments, the size and centering
+ * of the grid, the markers and dots, and the PNG resource.
+ ********************************************************************/
+package fynex
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestRenderPatternArguments(t *testing.T) {
+	pattern := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+	tests := []struct {
+		name          string
+		pattern       *PatternInfo
+		width, height int
+	}{
+		{"nil pattern", nil, 100, 100},
+		{"no mode", &PatternInfo{}, 100, 100},
+		{"no width", pattern, 0, 100},
+		{"negative height", pattern, 100, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RenderPattern(tt.pattern, tt.width, tt.height, PatternImageOptions{}); err == nil {
+				t.Error("RenderPattern() did not fail")
+			}
+			if _, err := RenderPatternPNG(tt.pattern, tt.width, tt.height, PatternImageOptions{}); err == nil {
+				t.Error("RenderPatternPNG() did not fail")
+			}
+		})
+	}
+}
+
+func TestRenderPattern(t *testing.T) {
+	// cells of 100 pixels, the dots at 50, 150 and 250
+	img, err := RenderPattern(mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), 300, 300, PatternImageOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 300, 300) {
+		t.Fatalf("bounds %v, want 300x300", img.Bounds())
+	}
+	checkPixels(t, img, []pixelCheck{
+		{"background", 2, 2, imageBackground},
+		{"start dot", 50, 50, imagePathColor},
+		{"start ring", 67, 50, imageStartColor},
+		{"end dot", 250, 250, imagePathColor},
+		{"end ring", 250, 267, imageEndColor},
+		{"idle dot", 50, 150, imageDotColor},
+		{"between idle dots", 100, 200, imageBackground},
+	})
+
+	// a wide image centers the grid, cells of 50 pixels from x=75
+	wide, _ := RenderPattern(mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), 300, 150, PatternImageOptions{
+		Background: color.Transparent,
+		StartColor: color.NRGBA{G: 0xff, A: 0xff},
+	})
+	checkPixels(t, wide, []pixelCheck{
+		{"left margin", 60, 25, color.Transparent},
+		{"start dot", 100, 25, imagePathColor},
+		{"start ring", 108, 25, color.NRGBA{G: 0xff, A: 0xff}},
+		{"idle dot", 100, 75, imageDotColor},
+	})
+}
+
+func TestRenderPatternNumbered(t *testing.T) {
+	img, err := RenderPattern(mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), 300, 300, PatternImageOptions{Numbered: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the digit is written on every visited dot, not on the others
+	written := func(x, y int) bool {
+		for dy := -6; dy <= 6; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				if sameColor(img.At(x+dx, y+dy), imageTextColor) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if !written(50, 50) || !written(250, 250) {
+		t.Error("the order is not written on the visited dots")
+	}
+	if written(50, 150) {
+		t.Error("an order is written on an idle dot")
+	}
+}
+
+func TestRenderPatternPNG(t *testing.T) {
+	pattern := mustPattern(t, []int{0, 1, 2, 3, 7}, PatternMode4x4)
+	res, err := RenderPatternPNG(pattern, 200, 120, PatternImageOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Name() != "pattern_4x4.png" {
+		t.Errorf("Name() = %q", res.Name())
+	}
+	img, err := png.Decode(bytes.NewReader(res.Content()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 200 || img.Bounds().Dy() != 120 {
+		t.Fatalf("decoded %v, want 200x120", img.Bounds())
+	}
+	rendered, _ := RenderPattern(pattern, 200, 120, PatternImageOptions{})
+	for _, pt := range []image.Point{{2, 2}, {55, 15}, {145, 45}, {100, 60}} {
+		if !sameColor(img.At(pt.X, pt.Y), rendered.At(pt.X, pt.Y)) {
+			t.Errorf("pixel %v is %v in the PNG, %v rendered", pt, img.At(pt.X, pt.Y), rendered.At(pt.X, pt.Y))
+		}
+	}
+}
+
+// the color expected at a pixel
+type pixelCheck struct {
+	name string
+	x, y int
+	want color.Color
+}
+
+func checkPixels(t *testing.T, img image.Image, checks []pixelCheck) {
+	t.Helper()
+	for _, check := range checks {
+		if got := img.At(check.x, check.y); !sameColor(got, check.want) {
+			t.Errorf("%s at %d,%d is %v, want %v", check.name, check.x, check.y, got, check.want)
+		}
+	}
+}
@@ -7,10 +7,7 @@
 package fynex
 
 import (
-	"errors"
 	"fmt"
-	"regexp"
-	"strings"
 )
 
 /* -----------------------------------------------------------------
@@ -41,30 +38,38 @@ type PatternInfo struct {
  * -----------------------------------------------------------------*/
 
 // define a new pattern using its internal format where each dot corresponds
-// to a 0-based index. The Lock Pattern is flattened as a single row.
+// to a 0-based index. The Lock Pattern is flattened as a single row. It
+// must satisfy the DefaultPatternRules.
 func NewPattern(pattern []int, mode PatternMode) (*PatternInfo, error) {
-	if mode == PatternModeNone {
-		return nil, errors.New("invalid pattern mode None")
+	return NewPatternWithRules(pattern, mode, DefaultPatternRules)
+}
+
+// (ctor) like NewPattern() but the pattern must satisfy the given rules
+// instead, nil means DefaultPatternRules. Whatever the rules, the dots
+// must be on the grid and a dot cannot follow itself.
+func NewPatternWithRules(pattern []int, mode PatternMode, rules PatternRule) (*PatternInfo, error) {
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("invalid pattern mode %s", mode)
 	}
-	if len(pattern) < mode.Width() {
-		return nil, fmt.Errorf("pattern mode %d needs at least %d dots", mode, mode.Width())
+	if err := validateIndices(pattern, mode); err != nil {
+		return nil, err
 	}
 	if dups := findAdjacentDuplicates(pattern); len(dups) != 0 {
 		return nil, fmt.Errorf("pattern has adjacent duplicates %v", dups)
 	}
-	if err := validateIndices(pattern, mode); err != nil {
+	if err := rulesOrDefault(cloneRules(rules)).Check(pattern, mode); err != nil {
 		return nil, err
 	}
 
 	return &PatternInfo{
 		mode:    mode,
-		minimum: uint8(mode.Width()),
+		minimum: uint8(mode.minSide()),
 		pattern: pattern,
 	}, nil
 }
 
 // define a new lock pattern using a human-friendly notation where each dot is
-// defined by its column A..E and its row 1..5. The letter and number limits
+// defined by its column A..I and its row 1..9. The letter and number limits
 // depend on the selected mode. The row number is 1-based.
 func NewPatternFromString(pattern string, mode PatternMode) (*PatternInfo, error) {
 	if internalPattern, err := ParseStringPatternFor(pattern, mode); err != nil {
@@ -88,11 +93,22 @@ func (pi *PatternInfo) Length() int {
 	return len(pi.pattern)
 }
 
-// The width or height size of the matrix for the selected mode
+// The width (columns) of the matrix for the selected mode. For the
+// square grids it is also the height.
 func (pi *PatternInfo) Size() int {
 	return pi.mode.Width()
 }
 
+// the number of columns of the grid for the selected mode
+func (pi *PatternInfo) Columns() int {
+	return pi.mode.Columns()
+}
+
+// the number of rows of the grid for the selected mode
+func (pi *PatternInfo) Rows() int {
+	return pi.mode.Rows()
+}
+
 // the current pattern mode. It dictates the validation requirements,
 // grid notation and grid size.
 func (pi *PatternInfo) Mode() PatternMode {
@@ -117,141 +133,84 @@ func (pi *PatternInfo) String() string {
  *                       F U N C T I O N S
  * -----------------------------------------------------------------*/
 
-// given a pattern mode like 3x3 or 4x4 and a sequence of dots,
-// convert the sequence from indices to friendly cartesian coordinates.
-func PatternInfoString(mode PatternMode, sequence []int) string {
-	if mode == PatternModeNone {
-		return ""
+// finds adjacent duplicates. We can have dots reconnecting at
+// some point, but never the same dot in sequence.
+func findAdjacentDuplicates[T int | string](slice []T) []T {
+	duplicates := make([]T, 0)
+
+	if len(slice) < 2 {
+		return duplicates
 	}
-	var mod = mode.Width()
-
-	coordSlice := make([]string, len(sequence))
-	for i, point := range sequence {
-		column := point % mod
-		row := int(point/mod) + 1 // 1-based
-		coord := fmt.Sprintf("%c%d", rune(65+column), row)
-		coordSlice[i] = coord
+
+	for i := 0; i < len(slice)-1; i++ {
+		if slice[i] == slice[i+1] {
+			duplicates = append(duplicates, slice[i])
+		}
 	}
 
-	return strings.Join(coordSlice, "-")
+	return duplicates
 }
 
-// takes a pattern in human-readable form and validates it for the selected
-// pattern mode. It ensures the dots are correct for the matrix size. It
-// also validates for adjacent duplicates.
-func ParseStringPatternFor(pattern string, mode PatternMode) ([]int, error) {
-	if mode == PatternModeNone {
-		return []int{}, errors.New("cannot parse for pattern mode None")
-	}
-	// remove whitespace
-	pattern = strings.Trim(pattern, " \t")
-	// normalize
-	pattern = strings.ToUpper(pattern)
-	// split in dots
-	const SEP = "-"
-	dots := strings.Split(pattern, SEP)
+// the dots lying exactly on the straight segment between two dots of a
+// grid that is width dots wide, in drawing order. For example A1 to C1
+// passes over B1, and A1 to C3 passes over B2.
+func intermediateDots(from, to int, width int) []int {
 	result := make([]int, 0)
-	if len(dots) < mode.Width() {
-		return []int{}, errors.New("not enough dots in the pattern")
-	}
-	// validate
-	for i, dot := range dots {
-		// The letters and numbers in the Pattern must match the ranges
-		// allowed for the maximum defined PatternMode: 3x3 ABC123,
-		// 4x4 ABCD1234 and 5x5 ABCDE12345
-		const PATTERN_3 = `^[A-C][1-3]$` // int: 0..9 hum: L[1..3]
-		const PATTERN_4 = `^[A-D][1-4]$` // int: 0..16 hum: L[1..4]
-		const PATTERN_5 = `^[A-E][1-5]$` // int: 0..24 hum: L[1..5]
-		var chosenPattern string
-		switch mode {
-		case PatternMode3x3:
-			chosenPattern = PATTERN_3
-		case PatternMode4x4:
-			chosenPattern = PATTERN_4
-		case PatternMode5x5:
-			chosenPattern = PATTERN_5
-		default:
-			return []int{}, errors.New("invalid pattern mode to ParseStringPattern")
-		}
-		re := regexp.MustCompile(chosenPattern)
-		if matched := re.MatchString(dot); matched {
-			// Column names are in ASCII so only 1-byte per letter
-			column := int(dot[0] - 'A') // 'A' = 65
-			row := int(dot[1] - '1')    // '1' = 49
-			// the human-friendly string rows are 1-based
-			index := row*int(mode.Width()) + column
-			// append the pattern dot in internal format (a 0-based slice index)
-			result = append(result, index)
-		} else {
-			return []int{}, fmt.Errorf("pattern dot error at %d='%s' invalid notation", i, dot)
-		}
+	if width <= 0 {
+		return result
 	}
 
-	// so far, so good, ensure the same dot is not adjacent to itself
-	if len(findAdjacentDuplicates(result)) != 0 {
-		return []int{}, errors.New("adjancent duplicates found")
+	dx := to%width - from%width
+	dy := to/width - from/width
+	steps := gcd(abs(dx), abs(dy))
+	for k := 1; k < steps; k++ {
+		col := from%width + k*dx/steps
+		row := from/width + k*dy/steps
+		result = append(result, row*width+col)
 	}
-
-	return result, nil
+	return result
 }
 
-// Parses and identifies a pattern. It ensures the dots are in the correct
-// human format, that the same dot does not repeat after itself and that it
-// has the minimum dots allowed for a mode. It begins with 3x3, then 4x4 and
-// if that also fails 5x5. Note that 3x3 patterns are perfectly valid for
-// 4x4, and 4x4 valid for 5x5; therefore the PatternMode may not be properly
-// identified in those circumstances. Otherwise parse specifically using the
-// ParseStringPatternFor() function.
-func ParseStringPattern(pattern string) ([]int, PatternMode, error) {
-	var err error = nil
-	var mode PatternMode = PatternMode3x3
-	var internalPattern []int
-	internalPattern, err = ParseStringPatternFor(pattern, mode)
-	if err != nil {
-		// Now let's try 4x4
-		mode = PatternMode4x4
-		internalPattern, err = ParseStringPatternFor(pattern, mode)
-		if err != nil {
-			// Now let's try 5x5
-			mode = PatternMode5x5
-			internalPattern, err = ParseStringPatternFor(pattern, mode)
+// the position of the first dot reached by jumping over an unvisited
+// dot, and the dot jumped over. The position is -1 if there is none.
+func findJump(indices []int, mode PatternMode) (at, mid int) {
+	visited := make(map[int]bool)
+	for i, index := range indices {
+		if i > 0 {
+			for _, mid := range intermediateDots(indices[i-1], index, mode.Width()) {
+				if !visited[mid] {
+					return i, mid
+				}
+			}
 		}
+		visited[index] = true
 	}
 
-	return internalPattern, mode, err
+	return -1, -1
 }
 
-// finds adjacent duplicates. We can have dots reconnecting at
-// some point, but never the same dot in sequence.
-func findAdjacentDuplicates[T int | string](slice []T) []T {
-	duplicates := make([]T, 0)
-
-	if len(slice) < 2 {
-		return duplicates
+// greatest common divisor of two non-negative integers
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
 	}
+	return a
+}
 
-	for i := 0; i < len(slice)-1; i++ {
-		if slice[i] == slice[i+1] {
-			duplicates = append(duplicates, slice[i])
-		}
+// absolute value of an integer
+func abs(n int) int {
+	if n < 0 {
+		return -n
 	}
-
-	return duplicates
+	return n
 }
 
 // validate the index value on the context of the selected PatternMode
 func validateIndices(indices []int, mode PatternMode) error {
-	var max int
-	switch mode {
-	case PatternMode3x3:
-		max = 8
-	case PatternMode4x4:
-		max = 15
-	case PatternMode5x5:
-		max = 24
-	default:
-		return errors.New("cannot validate indices for None")
+	if !mode.IsValid() {
+		return fmt.Errorf("cannot validate indices for %s", mode)
 	}
+	max := mode.Dots() - 1
 
 	for i, index := range indices {
 		// The internal index can never be negative
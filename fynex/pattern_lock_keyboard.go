@@ -0,0 +1,229 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Keyboard input for the PatternLock widget so that it can be used
+ * without a pointer. Arrow keys move a cursor over the grid, Space
+ * adds the dot under the cursor, Enter submits and Escape clears.
+ * Alternatively the pattern can be typed in A1-B2 notation.
+ ********************************************************************/
+package fynex
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const MSG_STATUS_CURSOR = "Dot %s"
+const MSG_STATUS_CURSOR_SELECTED = "Dot %s (selected)"
+const MSG_STATUS_CURSOR_HIDDEN = "%d of %d dots selected"
+const MSG_STATUS_TYPED = "Pattern: %s"
+const MSG_STATUS_NOTATION = "Invalid notation: %s"
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// implements fyne.Focusable
+func (p *PatternLock) FocusGained() {
+	p.focused = true
+	p.announceCursor()
+}
+
+// implements fyne.Focusable
+func (p *PatternLock) FocusLost() {
+	p.focused = false
+	p.Refresh()
+}
+
+// Implements fyne.Shortcutable. The shortcut goes on to the canvas as
+// if the widget did not handle it, unless an IdleLocker blocks them.
+func (p *PatternLock) TypedShortcut(shortcut fyne.Shortcut) {
+	p.mux.Lock()
+	blocked := p.shortcutsBlocked
+	p.mux.Unlock()
+
+	if blocked || fyne.CurrentApp() == nil {
+		return
+	}
+	if c, ok := fyne.CurrentApp().Driver().CanvasForObject(p).(fyne.Shortcutable); ok {
+		c.TypedShortcut(shortcut)
+	}
+}
+
+// implements fyne.Focusable. Letters, digits and separators build up a
+// pattern in A1-B2 (or 0,4) notation that is submitted with Enter.
+func (p *PatternLock) TypedRune(r rune) {
+	if p.inputBlocked() {
+		return
+	}
+	if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != ',' {
+		return
+	}
+	p.stopAnimation()
+	p.beginSession()
+	p.typed += string(unicode.ToUpper(r))
+	p.SetStatus(fmt.Sprintf(p.message(MsgStatusTyped), maskNotation(p.typed)))
+}
+
+// implements fyne.Focusable
+func (p *PatternLock) TypedKey(e *fyne.KeyEvent) {
+	if p.inputBlocked() {
+		return
+	}
+
+	switch e.Name {
+	case fyne.KeyLeft:
+		p.moveCursor(-1, 0)
+	case fyne.KeyRight:
+		p.moveCursor(1, 0)
+	case fyne.KeyUp:
+		p.moveCursor(0, -1)
+	case fyne.KeyDown:
+		p.moveCursor(0, 1)
+
+	case fyne.KeySpace:
+		if !p.isDrawing() {
+			p.stopAnimation()
+		}
+		if p.addDot(p.cursor) {
+			p.active = true
+		}
+		p.announceCursor()
+
+	case fyne.KeyBackspace:
+		if len(p.typed) > 0 {
+			p.typed = p.typed[:len(p.typed)-1]
+			p.SetStatus(fmt.Sprintf(p.message(MsgStatusTyped), maskNotation(p.typed)))
+		}
+
+	case fyne.KeyReturn, fyne.KeyEnter:
+		p.submitKeyboard()
+
+	case fyne.KeyEscape:
+		log.Print("Keyboard input cleared")
+		p.stopAnimation()
+		p.typed = ""
+		p.active = false
+		p.Sequence = []int{}
+		p.endSession(TraceReset)
+		p.SetStatus(p.idleStatus())
+	}
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// moves the keyboard cursor, it stops at the grid borders
+func (p *PatternLock) moveCursor(dx, dy int) {
+	columns, rows := p.gridMode.Columns(), p.gridMode.Rows()
+	col := min(max(p.cursor%columns+dx, 0), columns-1)
+	row := min(max(p.cursor/columns+dy, 0), rows-1)
+	p.cursor = row*columns + col
+	p.announceCursor()
+}
+
+// Shows the dot under the cursor in the status label so that it can
+// be read aloud by assistive technologies. In stealth mode that would
+// give the pattern away, only the number of dots selected is shown.
+func (p *PatternLock) announceCursor() {
+	if p.cursor < 0 || p.cursor >= p.gridMode.Dots() {
+		p.cursor = 0
+	}
+	switch {
+	case p.Stealth() != StealthOff:
+		p.SetStatus(fmt.Sprintf(p.message(MsgStatusCursorHidden), len(p.Sequence), p.gridMode.Dots()))
+	case p.isVisited(p.cursor):
+		p.SetStatus(fmt.Sprintf(p.message(MsgStatusCursorSelected), p.getPos(p.cursor)))
+	default:
+		p.SetStatus(fmt.Sprintf(p.message(MsgStatusCursor), p.getPos(p.cursor)))
+	}
+}
+
+// submits the typed notation, or else the dots added with Space, just
+// like releasing the pointer at the end of a drag.
+func (p *PatternLock) submitKeyboard() {
+	if p.animation != nil {
+		// the animated path is a playback or a past result, not an input
+		p.stopAnimation()
+	}
+	if p.typed != "" {
+		p.mux.Lock()
+		rules := p.rules
+		p.mux.Unlock()
+		sequence, err := ParseStringPatternWithRules(p.typed, p.gridMode, rules)
+		p.typed = ""
+		if err != nil {
+			log.Print("Typed pattern refused: ", err)
+			p.Sequence = []int{}
+			p.endSession(TraceCancelled)
+			p.SetStatus(fmt.Sprintf(p.message(MsgStatusNotation), err))
+			return
+		}
+		// the typed dots replace any added with Space, all reached at once
+		p.Sequence = sequence
+		p.beginSession()
+		p.session.Dots = []TracedDot{}
+		for _, index := range sequence {
+			p.traceDot(index)
+		}
+	}
+	p.DragEnd()
+}
+
+// the status to show when no pattern is being entered
+func (p *PatternLock) idleStatus() string {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	switch {
+	case p.designing && p.designStep == designConfirm:
+		return p.messages.Get(MsgStatusConfirm)
+	case p.designing:
+		return p.messages.Get(MsgStatusDefine)
+	}
+	return p.messages.Get(MsgStatusUnblock)
+}
+
+// Drops the shortcuts typed while the widget has the focus, instead of
+// passing them to the canvas
+func (p *PatternLock) blockShortcuts(blocked bool) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.shortcutsBlocked = blocked
+}
+
+// gives this widget the keyboard focus, if it is on a canvas
+func (p *PatternLock) requestFocus() {
+	if fyne.CurrentApp() == nil {
+		return
+	}
+	if c := fyne.CurrentApp().Driver().CanvasForObject(p); c != nil {
+		c.Focus(p)
+	}
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// hides the typed dots from onlookers but keeps the separators so the
+// user can count them, e.g. "A1-B" is shown as "**-*"
+func maskNotation(typed string) string {
+	return strings.Map(func(r rune) rune {
+		if isNotationSeparator(r) {
+			return r
+		}
+		return '*'
+	}, typed)
+}
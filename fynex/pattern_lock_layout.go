@@ -0,0 +1,226 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Layout of the PatternLock widget: where the status message goes
+ * and the geometry of the grid. The same geometry is used to draw
+ * the dots and to hit-test the pointer, and it scales with the theme.
+ ********************************************************************/
+package fynex
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// the status message is shown above the grid (default)
+	StatusTop StatusPlacement = iota
+	// the status message is shown below the grid
+	StatusBottom
+	// there is no status message, the grid takes the whole widget
+	StatusHidden
+	// the status message is sent to another widget, see SetStatusWidget()
+	StatusExternal
+)
+
+// the minimum width of the grid, its height follows the rows
+const gridMIN_WIDTH = 300
+
+/* -----------------------------------------------------------------
+ *                     I N T E R F A C E S
+ * -----------------------------------------------------------------*/
+
+// Anything that can display the status message, like a widget.Label
+// or a DynamicLabel.
+type StatusDisplay interface {
+	SetText(string)
+}
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// Where the PatternLock shows its status message
+type StatusPlacement uint8
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// the position of the status area and of the grid cells for a given
+// widget size
+type patternGeometry struct {
+	columns    int
+	rows       int
+	status     fyne.Position // top-left of the status area
+	statusSize fyne.Size     // zero when the status is not shown inside
+	grid       fyne.Position // top-left of the grid
+	gridSize   fyne.Size
+	cell       fyne.Size // size of one grid cell
+}
+
+// the last geometry and what it was computed for
+type geometryCache struct {
+	size         fyne.Size
+	placement    StatusPlacement
+	statusHeight float32
+	geometry     patternGeometry
+	valid        bool
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// Chooses where the status message is shown. StatusExternal is set by
+// SetStatusWidget(), without a widget it behaves like StatusHidden.
+func (p *PatternLock) SetStatusPlacement(placement StatusPlacement) *PatternLock {
+	p.mux.Lock()
+	p.statusPlacement = placement
+	p.mux.Unlock()
+
+	p.refreshUI()
+	return p
+}
+
+// Sends the status message to another widget instead of showing it in
+// the PatternLock, which then uses all its space for the grid. A nil
+// target puts the status back on top.
+func (p *PatternLock) SetStatusWidget(target StatusDisplay) *PatternLock {
+	p.mux.Lock()
+	p.statusTarget = target
+	p.statusRetarget = true
+	if target != nil {
+		p.statusPlacement = StatusExternal
+	} else {
+		p.statusPlacement = StatusTop
+	}
+	p.mux.Unlock()
+
+	p.refreshUI()
+	return p
+}
+
+// the current placement of the status message
+func (p *PatternLock) StatusPlacement() StatusPlacement {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.statusPlacement
+}
+
+// the center of the dot in widget coordinates
+func (g patternGeometry) dotCenter(index int) fyne.Position {
+	if g.columns == 0 {
+		return fyne.NewPos(0, 0)
+	}
+	column, row := index%g.columns, index/g.columns
+	return g.grid.AddXY(g.cell.Width*(float32(column)+0.5), g.cell.Height*(float32(row)+0.5))
+}
+
+// the radius of the drawn dots, ratio is a fraction of the cell size
+func (g patternGeometry) dotRadius(ratio float32) float32 {
+	return fyne.Min(g.cell.Width, g.cell.Height) * ratio
+}
+
+// The distance from a dot center within which the pointer hits it,
+// ratio is a fraction of the cell size. It never leaves the cell.
+func (g patternGeometry) hitRadius(ratio float32) float32 {
+	return fyne.Min(g.cell.Width, g.cell.Height) * fyne.Min(ratio, maxHIT_RADIUS_RATIO)
+}
+
+// the dot whose cell contains the position, -1 if outside the grid
+func (g patternGeometry) cellAt(pos fyne.Position) int {
+	if g.cell.Width <= 0 || g.cell.Height <= 0 {
+		return -1
+	}
+	local := pos.Subtract(g.grid)
+	if local.X < 0 || local.Y < 0 {
+		return -1
+	}
+	column, row := int(local.X/g.cell.Width), int(local.Y/g.cell.Height)
+	if column >= g.columns || row >= g.rows {
+		return -1
+	}
+	return row*g.columns + column
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// The layout for the given widget size. This is the single source of
+// geometry for the renderer and for hit-testing, so the last one is
+// kept until the size, the status placement or the theme change.
+func (p *PatternLock) geometry(size fyne.Size) patternGeometry {
+	th := p.Theme()
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	placement := p.statusPlacement
+	statusHeight := p.statusHeightLocked(th)
+	cached := &p.layout
+	if cached.valid && cached.size == size && cached.placement == placement && cached.statusHeight == statusHeight {
+		return cached.geometry
+	}
+
+	g := patternGeometry{columns: p.gridMode.Columns(), rows: p.gridMode.Rows()}
+	g.gridSize = fyne.NewSize(size.Width, max(size.Height-statusHeight, 0))
+	switch placement {
+	case StatusTop:
+		g.statusSize = fyne.NewSize(size.Width, statusHeight)
+		g.grid = fyne.NewPos(0, statusHeight)
+	case StatusBottom:
+		g.statusSize = fyne.NewSize(size.Width, statusHeight)
+		g.status = fyne.NewPos(0, g.gridSize.Height)
+	}
+	if g.columns > 0 && g.rows > 0 {
+		g.cell = fyne.NewSize(g.gridSize.Width/float32(g.columns), g.gridSize.Height/float32(g.rows))
+	}
+	*cached = geometryCache{size: size, placement: placement, statusHeight: statusHeight, geometry: g, valid: true}
+	return g
+}
+
+// The height of the status area inside the widget, zero when it is not
+// shown there. The caller holds the lock.
+func (p *PatternLock) statusHeightLocked(th fyne.Theme) float32 {
+	if p.statusPlacement != StatusTop && p.statusPlacement != StatusBottom {
+		return 0
+	}
+	return p.resolveLocked(th, themeVariant()).statusHeight
+}
+
+// the minimum size of the widget: a grid of square cells that is
+// gridMIN_WIDTH wide plus the status area
+func (p *PatternLock) minSize() fyne.Size {
+	columns, rows := p.gridMode.Columns(), p.gridMode.Rows()
+	gridHeight := float32(gridMIN_WIDTH)
+	if columns > 0 {
+		gridHeight = gridMIN_WIDTH * float32(rows) / float32(columns)
+	}
+	th := p.Theme()
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return fyne.NewSize(gridMIN_WIDTH, gridHeight+p.statusHeightLocked(th))
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// The height of a status area shown inside the widget: one line of bold
+// text plus the theme padding, so it follows the theme text size.
+func statusHeight(th fyne.Theme) float32 {
+	textSize := th.Size(theme.SizeNameText)
+	textHeight := textSize * 1.4 // typical line height without a driver
+	if fyne.CurrentApp() != nil {
+		textHeight = fyne.MeasureText("Mg", textSize, fyne.TextStyle{Bold: true}).Height
+	}
+	return textHeight + 2*th.Size(theme.SizeNameInnerPadding) + th.Size(theme.SizeNamePadding)
+}
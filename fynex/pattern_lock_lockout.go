@@ -0,0 +1,204 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Failed-attempt throttling for the PatternLock widget. After too
+ * many wrong patterns the widget locks itself for an escalating
+ * cooldown period. The counters can survive an application restart
+ * by persisting them in the fyne.Preferences.
+ ********************************************************************/
+package fynex
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	lockoutKEY_FAILURES = ".failures"
+	lockoutKEY_LEVEL    = ".level"
+	lockoutKEY_UNTIL    = ".until"
+)
+
+// The escalating cooldowns used when none are given: the first lockout
+// lasts 30 seconds, the second one minute and any further one 5 minutes.
+var DefaultCooldowns = []time.Duration{30 * time.Second, time.Minute, 5 * time.Minute}
+
+/* -----------------------------------------------------------------
+ *                     I N T E R F A C E S
+ * -----------------------------------------------------------------*/
+
+// A LockoutPolicy decides when the PatternLock must stop accepting
+// input after failed attempts, and for how long.
+type LockoutPolicy interface {
+	// records a failed attempt and returns the cooldown that must elapse
+	// before the next attempt. Zero means no lockout.
+	RecordFailure(now time.Time) time.Duration
+	// records a successful attempt
+	RecordSuccess()
+	// the moment the current lockout ends, zero if not locked
+	LockedUntil() time.Time
+}
+
+var _ LockoutPolicy = (*AttemptLockout)(nil)
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// A LockoutPolicy that locks after MaxAttempts consecutive failures.
+// Every lockout uses the next cooldown in the list, the last one is
+// reused once the list is exhausted. A success resets everything.
+type AttemptLockout struct {
+	MaxAttempts int
+	Cooldowns   []time.Duration
+
+	failures int       // consecutive failures since the last lockout
+	level    int       // how many lockouts we had so far
+	until    time.Time // end of the current lockout
+	prefs    fyne.Preferences
+	prefsKey string
+	mux      sync.Mutex
+}
+
+/* -----------------------------------------------------------------
+ *                  C O N S T R U C T O R S
+ * -----------------------------------------------------------------*/
+
+// (ctor) a lockout policy allowing maxAttempts consecutive failures
+// before locking for the given (escalating) cooldowns. If no cooldowns
+// are given DefaultCooldowns is used.
+func NewAttemptLockout(maxAttempts int, cooldowns ...time.Duration) *AttemptLockout {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	if len(cooldowns) == 0 {
+		cooldowns = DefaultCooldowns
+	}
+	return &AttemptLockout{
+		MaxAttempts: maxAttempts,
+		Cooldowns:   cooldowns,
+	}
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// Persist the counters in the application preferences under the given
+// key prefix (for example "lock.main"). Any previously saved state is
+// loaded right away so that a restart does not reset the counters.
+func (a *AttemptLockout) WithPreferences(prefs fyne.Preferences, key string) *AttemptLockout {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	a.prefs = prefs
+	a.prefsKey = key
+	a.load()
+	return a
+}
+
+// implements LockoutPolicy
+func (a *AttemptLockout) RecordFailure(now time.Time) time.Duration {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	cooldowns := a.Cooldowns
+	if len(cooldowns) == 0 {
+		cooldowns = DefaultCooldowns
+	}
+
+	var cooldown time.Duration
+	a.failures++
+	if a.failures >= a.MaxAttempts {
+		cooldown = cooldowns[min(a.level, len(cooldowns)-1)]
+		a.level++
+		a.failures = 0
+		a.until = now.Add(cooldown)
+	}
+	a.save()
+	return cooldown
+}
+
+// implements LockoutPolicy
+func (a *AttemptLockout) RecordSuccess() {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	a.failures = 0
+	a.level = 0
+	a.until = time.Time{}
+	a.save()
+}
+
+// implements LockoutPolicy
+func (a *AttemptLockout) LockedUntil() time.Time {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	return a.until
+}
+
+// the number of consecutive failures since the last lockout
+func (a *AttemptLockout) Failures() int {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	return a.failures
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// load the counters from the preferences (if any)
+func (a *AttemptLockout) load() {
+	if a.prefs == nil {
+		return
+	}
+	a.failures = a.prefs.Int(a.prefsKey + lockoutKEY_FAILURES)
+	a.level = a.prefs.Int(a.prefsKey + lockoutKEY_LEVEL)
+	a.until = time.Time{}
+	if ms, err := strconv.ParseInt(a.prefs.String(a.prefsKey+lockoutKEY_UNTIL), 10, 64); err == nil && ms > 0 {
+		a.until = time.UnixMilli(ms)
+	}
+}
+
+// save the counters to the preferences (if any)
+func (a *AttemptLockout) save() {
+	if a.prefs == nil {
+		return
+	}
+	var until int64
+	if !a.until.IsZero() {
+		until = a.until.UnixMilli()
+	}
+	a.prefs.SetInt(a.prefsKey+lockoutKEY_FAILURES, a.failures)
+	a.prefs.SetInt(a.prefsKey+lockoutKEY_LEVEL, a.level)
+	a.prefs.SetString(a.prefsKey+lockoutKEY_UNTIL, strconv.FormatInt(until, 10))
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// the remaining lockout time rounded up to whole seconds so that the
+// countdown never shows 0s while still locked.
+func lockoutRemaining(until, now time.Time) time.Duration {
+	remaining := until.Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+	rounded := remaining.Truncate(time.Second)
+	if rounded < remaining {
+		rounded += time.Second
+	}
+	return rounded
+}
@@ -0,0 +1,124 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the failed-attempt lockout policy: escalating cooldowns,
+ * reset on success, persistence and the countdown rounding.
+ ********************************************************************/
+package fynex
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestAttemptLockoutEscalation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	lockout := NewAttemptLockout(3, 10*time.Second, time.Minute)
+
+	// each lockout takes three failures, the last cooldown is reused
+	want := []time.Duration{
+		0, 0, 10 * time.Second,
+		0, 0, time.Minute,
+		0, 0, time.Minute,
+	}
+	for attempt, cooldown := range want {
+		if got := lockout.RecordFailure(now); got != cooldown {
+			t.Fatalf("failure %d: cooldown = %s, want %s", attempt+1, got, cooldown)
+		}
+		if cooldown > 0 && !lockout.LockedUntil().Equal(now.Add(cooldown)) {
+			t.Errorf("failure %d: locked until %s, want %s", attempt+1, lockout.LockedUntil(), now.Add(cooldown))
+		}
+	}
+
+	lockout.RecordFailure(now)
+	lockout.RecordSuccess()
+	if lockout.Failures() != 0 || !lockout.LockedUntil().IsZero() {
+		t.Errorf("after a success: %d failures, locked until %s", lockout.Failures(), lockout.LockedUntil())
+	}
+	// the escalation starts over
+	lockout.RecordFailure(now)
+	lockout.RecordFailure(now)
+	if got := lockout.RecordFailure(now); got != 10*time.Second {
+		t.Errorf("cooldown after a success = %s, want 10s", got)
+	}
+}
+
+func TestAttemptLockoutDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		cooldowns   []time.Duration
+		wantFirst   time.Duration // cooldown of the failure that locks
+		wantLocking int           // failures until the lockout
+	}{
+		{"default cooldowns", 2, nil, DefaultCooldowns[0], 2},
+		{"at least one attempt", 0, []time.Duration{time.Second}, time.Second, 1},
+		{"negative attempts", -5, []time.Duration{time.Second}, time.Second, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lockout := NewAttemptLockout(tt.maxAttempts, tt.cooldowns...)
+			var cooldown time.Duration
+			failures := 0
+			for cooldown == 0 && failures < 10 {
+				cooldown = lockout.RecordFailure(time.Now())
+				failures++
+			}
+			if cooldown != tt.wantFirst || failures != tt.wantLocking {
+				t.Errorf("locked for %s after %d failures, want %s after %d", cooldown, failures, tt.wantFirst, tt.wantLocking)
+			}
+		})
+	}
+}
+
+func TestAttemptLockoutPreferences(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	now := time.Now().Truncate(time.Millisecond)
+
+	first := NewAttemptLockout(2, time.Hour).WithPreferences(prefs, "lock.test")
+	first.RecordFailure(now)
+	first.RecordFailure(now)
+
+	// a restart must not reset the counters
+	second := NewAttemptLockout(2, time.Hour).WithPreferences(prefs, "lock.test")
+	if !second.LockedUntil().Equal(now.Add(time.Hour)) {
+		t.Errorf("restored lockout until %s, want %s", second.LockedUntil(), now.Add(time.Hour))
+	}
+	second.RecordFailure(now)
+	third := NewAttemptLockout(2, time.Hour).WithPreferences(prefs, "lock.test")
+	if third.Failures() != 1 {
+		t.Errorf("restored %d failures, want 1", third.Failures())
+	}
+
+	// other keys are other locks
+	if other := NewAttemptLockout(2).WithPreferences(prefs, "lock.other"); !other.LockedUntil().IsZero() {
+		t.Errorf("another key is locked until %s", other.LockedUntil())
+	}
+}
+
+func TestLockoutRemaining(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		until time.Time
+		want  time.Duration
+	}{
+		{now.Add(-time.Second), 0},
+		{now, 0},
+		{now.Add(time.Millisecond), time.Second},
+		{now.Add(time.Second), time.Second},
+		{now.Add(1500 * time.Millisecond), 2 * time.Second},
+		{now.Add(30 * time.Second), 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := lockoutRemaining(tt.until, now); got != tt.want {
+			t.Errorf("lockoutRemaining(%s) = %s, want %s", tt.until.Sub(now), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Text, JSON and binary (un)marshaling of PatternMode & PatternInfo
+ * so that patterns can be saved to configuration files or sent over
+ * the wire. Decoding always goes through NewPattern() so a malformed
+ * input can never produce an invalid pattern.
+ ********************************************************************/
+package fynex
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// version of the PatternInfo binary layout
+	patternBINARY_VERSION byte = 1
+	// separates the mode from the dots in the text form (3x3:A1-B1-C1)
+	patternMODE_SEPARATOR = ":"
+)
+
+/* -----------------------------------------------------------------
+ *                     I N T E R F A C E S
+ * -----------------------------------------------------------------*/
+
+var _ encoding.TextMarshaler = PatternMode(0)
+var _ encoding.TextUnmarshaler = (*PatternMode)(nil)
+var _ encoding.BinaryMarshaler = PatternMode(0)
+var _ encoding.BinaryUnmarshaler = (*PatternMode)(nil)
+
+// values marshal too, e.g. in a struct field or a map
+var _ encoding.TextMarshaler = PatternInfo{}
+var _ encoding.TextUnmarshaler = (*PatternInfo)(nil)
+var _ encoding.BinaryMarshaler = PatternInfo{}
+var _ encoding.BinaryUnmarshaler = (*PatternInfo)(nil)
+var _ json.Marshaler = PatternInfo{}
+var _ json.Unmarshaler = (*PatternInfo)(nil)
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// the JSON object form of a PatternInfo
+type patternInfoJSON struct {
+	Mode    PatternMode `json:"mode"`
+	Pattern string      `json:"pattern"`
+}
+
+/* -----------------------------------------------------------------
+ *                  P A T T E R N   M O D E
+ * -----------------------------------------------------------------*/
+
+// implements encoding.TextMarshaler (also used by encoding/json)
+func (pm PatternMode) MarshalText() ([]byte, error) {
+	if pm.String() == "" {
+		return nil, fmt.Errorf("cannot marshal unknown pattern mode %d", uint8(pm))
+	}
+	return []byte(pm.String()), nil
+}
+
+// implements encoding.TextUnmarshaler (also used by encoding/json)
+func (pm *PatternMode) UnmarshalText(text []byte) error {
+	mode, err := ParsePatternMode(string(text))
+	if err != nil {
+		return err
+	}
+	*pm = mode
+	return nil
+}
+
+// implements encoding.BinaryMarshaler as a single byte
+func (pm PatternMode) MarshalBinary() ([]byte, error) {
+	if pm.String() == "" {
+		return nil, fmt.Errorf("cannot marshal unknown pattern mode %d", uint8(pm))
+	}
+	return []byte{byte(pm)}, nil
+}
+
+// implements encoding.BinaryUnmarshaler
+func (pm *PatternMode) UnmarshalBinary(data []byte) error {
+	if len(data) != 1 {
+		return errors.New("pattern mode must be a single byte")
+	}
+	mode := PatternMode(data[0])
+	if mode.String() == "" {
+		return fmt.Errorf("unknown pattern mode %d", data[0])
+	}
+	*pm = mode
+	return nil
+}
+
+/* -----------------------------------------------------------------
+ *                  P A T T E R N   I N F O
+ * -----------------------------------------------------------------*/
+
+// implements encoding.TextMarshaler with the mode-prefixed friendly
+// notation, for example "3x3:A1-B1-C1-C2-C3"
+func (pi PatternInfo) MarshalText() ([]byte, error) {
+	if pi.mode == PatternModeNone {
+		return nil, errors.New("cannot marshal pattern with mode None")
+	}
+	return []byte(pi.mode.String() + patternMODE_SEPARATOR + pi.String()), nil
+}
+
+// implements encoding.TextUnmarshaler for the "MODE:DOTS" notation
+func (pi *PatternInfo) UnmarshalText(text []byte) error {
+	modeText, dots, found := strings.Cut(string(text), patternMODE_SEPARATOR)
+	if !found {
+		return errors.New("pattern text lacks the mode prefix")
+	}
+	mode, err := ParsePatternMode(modeText)
+	if err != nil {
+		return err
+	}
+	return pi.decodeFriendly(dots, mode)
+}
+
+// implements json.Marshaler as {"mode":"3x3","pattern":"A1-B1-C1"}
+func (pi PatternInfo) MarshalJSON() ([]byte, error) {
+	if pi.mode == PatternModeNone {
+		return nil, errors.New("cannot marshal pattern with mode None")
+	}
+	return json.Marshal(patternInfoJSON{
+		Mode:    pi.mode,
+		Pattern: pi.String(),
+	})
+}
+
+// implements json.Unmarshaler
+func (pi *PatternInfo) UnmarshalJSON(data []byte) error {
+	var aux patternInfoJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return pi.decodeFriendly(aux.Pattern, aux.Mode)
+}
+
+// implements encoding.BinaryMarshaler. The layout is one byte each for
+// the format version, the mode and the dot count, followed by one
+// byte per dot index.
+func (pi PatternInfo) MarshalBinary() ([]byte, error) {
+	if pi.mode == PatternModeNone {
+		return nil, errors.New("cannot marshal pattern with mode None")
+	}
+	if len(pi.pattern) > 255 {
+		return nil, errors.New("pattern too long for binary form")
+	}
+
+	data := make([]byte, 0, 3+len(pi.pattern))
+	data = append(data, patternBINARY_VERSION, byte(pi.mode), byte(len(pi.pattern)))
+	for _, index := range pi.pattern {
+		data = append(data, byte(index))
+	}
+	return data, nil
+}
+
+// implements encoding.BinaryUnmarshaler
+func (pi *PatternInfo) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 {
+		return errors.New("pattern binary data too short")
+	}
+	if data[0] != patternBINARY_VERSION {
+		return fmt.Errorf("unsupported pattern binary version %d", data[0])
+	}
+	var mode PatternMode
+	if err := mode.UnmarshalBinary(data[1:2]); err != nil {
+		return err
+	}
+	count := int(data[2])
+	if len(data) != 3+count {
+		return fmt.Errorf("pattern binary data has %d dots, header says %d", len(data)-3, count)
+	}
+
+	sequence := make([]int, count)
+	for i, b := range data[3:] {
+		sequence[i] = int(b)
+	}
+	return pi.assign(sequence, mode)
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// parse the friendly notation and assign it to the receiver
+func (pi *PatternInfo) decodeFriendly(dots string, mode PatternMode) error {
+	sequence, err := ParseStringPatternFor(dots, mode)
+	if err != nil {
+		return err
+	}
+	return pi.assign(sequence, mode)
+}
+
+// validate through NewPattern() and only then overwrite the receiver
+func (pi *PatternInfo) assign(sequence []int, mode PatternMode) error {
+	decoded, err := NewPattern(sequence, mode)
+	if err != nil {
+		return err
+	}
+	*pi = *decoded
+	return nil
+}
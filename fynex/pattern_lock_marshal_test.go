@@ -0,0 +1,172 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the text, JSON and binary forms of PatternInfo and
+ * PatternMode: round trips, values in containers and malformed input.
+ ********************************************************************/
+package fynex
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestPatternInfoRoundTrip(t *testing.T) {
+	patterns := []*PatternInfo{
+		mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3),
+		mustPattern(t, []int{15, 10, 5, 0, 1, 2, 3}, PatternMode4x4),
+		mustPattern(t, []int{0, 6, 12, 18, 24}, PatternMode5x5),
+	}
+	codecs := []struct {
+		name      string
+		marshal   func(*PatternInfo) ([]byte, error)
+		unmarshal func(*PatternInfo, []byte) error
+	}{
+		{"text", func(pi *PatternInfo) ([]byte, error) { return pi.MarshalText() }, (*PatternInfo).UnmarshalText},
+		{"json", func(pi *PatternInfo) ([]byte, error) { return json.Marshal(pi) }, func(pi *PatternInfo, data []byte) error {
+			return json.Unmarshal(data, pi)
+		}},
+		{"binary", func(pi *PatternInfo) ([]byte, error) { return pi.MarshalBinary() }, (*PatternInfo).UnmarshalBinary},
+	}
+	for _, codec := range codecs {
+		for _, pattern := range patterns {
+			t.Run(codec.name+"/"+pattern.Mode().String(), func(t *testing.T) {
+				data, err := codec.marshal(pattern)
+				if err != nil {
+					t.Fatalf("marshal error = %v", err)
+				}
+				decoded := &PatternInfo{}
+				if err := codec.unmarshal(decoded, data); err != nil {
+					t.Fatalf("unmarshal(%q) error = %v", data, err)
+				}
+				if !reflect.DeepEqual(decoded, pattern) {
+					t.Errorf("round trip = %+v, want %+v", decoded, pattern)
+				}
+			})
+		}
+	}
+}
+
+func TestPatternInfoValueMarshal(t *testing.T) {
+	pattern := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+	want := `{"mode":"3x3","pattern":"A1-B1-C1-C2-C3"}`
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"value", *pattern, want},
+		{"pointer", pattern, want},
+		{"struct field", struct{ Lock PatternInfo }{*pattern}, `{"Lock":` + want + `}`},
+		{"map value", map[string]PatternInfo{"alice": *pattern}, `{"alice":` + want + `}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatternInfoMalformed(t *testing.T) {
+	tests := []struct {
+		name      string
+		unmarshal func(*PatternInfo) error
+	}{
+		{"text without mode", func(pi *PatternInfo) error {
+			return pi.UnmarshalText([]byte("A1-B1-C1"))
+		}},
+		{"text unknown mode", func(pi *PatternInfo) error {
+			return pi.UnmarshalText([]byte("2x2:A1-B1-B2"))
+		}},
+		{"text off the grid", func(pi *PatternInfo) error {
+			return pi.UnmarshalText([]byte("3x3:A1-B1-D1"))
+		}},
+		{"json syntax", func(pi *PatternInfo) error {
+			return json.Unmarshal([]byte(`{"mode":"3x3","pattern":`), pi)
+		}},
+		{"json too short", func(pi *PatternInfo) error {
+			return json.Unmarshal([]byte(`{"mode":"3x3","pattern":"A1-B1"}`), pi)
+		}},
+		{"json adjacent duplicate", func(pi *PatternInfo) error {
+			return json.Unmarshal([]byte(`{"mode":"3x3","pattern":"A1-A1-B1-C1"}`), pi)
+		}},
+		{"json unknown mode", func(pi *PatternInfo) error {
+			return json.Unmarshal([]byte(`{"mode":"10x10","pattern":"A1-B1-C1"}`), pi)
+		}},
+		{"binary too short", func(pi *PatternInfo) error {
+			return pi.UnmarshalBinary([]byte{patternBINARY_VERSION, byte(PatternMode3x3)})
+		}},
+		{"binary version", func(pi *PatternInfo) error {
+			return pi.UnmarshalBinary([]byte{patternBINARY_VERSION + 1, byte(PatternMode3x3), 3, 0, 1, 2})
+		}},
+		{"binary count", func(pi *PatternInfo) error {
+			return pi.UnmarshalBinary([]byte{patternBINARY_VERSION, byte(PatternMode3x3), 4, 0, 1, 2})
+		}},
+		{"binary off the grid", func(pi *PatternInfo) error {
+			return pi.UnmarshalBinary([]byte{patternBINARY_VERSION, byte(PatternMode3x3), 3, 0, 1, 9})
+		}},
+		{"binary too short a pattern", func(pi *PatternInfo) error {
+			return pi.UnmarshalBinary([]byte{patternBINARY_VERSION, byte(PatternMode3x3), 2, 0, 1})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)
+			decoded := *original
+			if err := tt.unmarshal(&decoded); err == nil {
+				t.Fatal("a malformed document was accepted")
+			}
+			if !reflect.DeepEqual(&decoded, original) {
+				t.Errorf("a refused document changed the pattern to %+v", decoded)
+			}
+		})
+	}
+
+	// a marshaled pattern that NewPattern() would refuse
+	if _, err := (PatternInfo{}).MarshalText(); err == nil {
+		t.Error("a pattern without mode was marshaled")
+	}
+}
+
+func TestPatternModeMarshal(t *testing.T) {
+	for _, mode := range []PatternMode{PatternMode3x3, PatternMode4x4, PatternMode5x5} {
+		text, err := mode.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromText PatternMode
+		if err := fromText.UnmarshalText(text); err != nil || fromText != mode {
+			t.Errorf("text round trip of %s = %s, %v", mode, fromText, err)
+		}
+
+		data, err := mode.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromBinary PatternMode
+		if err := fromBinary.UnmarshalBinary(data); err != nil || fromBinary != mode {
+			t.Errorf("binary round trip of %s = %s, %v", mode, fromBinary, err)
+		}
+	}
+
+	var mode PatternMode
+	for _, text := range []string{"", "3x", "x3", "2x2", "10x10", strings.Repeat("3", 20)} {
+		if err := mode.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) accepted", text)
+		}
+	}
+}
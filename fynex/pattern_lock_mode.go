@@ -7,7 +7,11 @@
  *-----------------------------------------------------------------*/
 package fynex
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 /* ----------------------------------------------------------------
  *                       G L O B A L S
@@ -20,79 +24,131 @@ const (
 	PatternMode5x5
 )
 
+// Larger square grids. Any other grid can be obtained with
+// NewPatternMode(columns, rows).
+const (
+	PatternMode6x6 PatternMode = 6<<4 | 6
+	PatternMode7x7 PatternMode = 7<<4 | 7
+	PatternMode8x8 PatternMode = 8<<4 | 8
+	PatternMode9x9 PatternMode = 9<<4 | 9
+)
+
+const (
+	// the smallest number of columns or rows of a grid
+	PatternGridMin = 3
+	// the largest number of columns (A..I) or rows (1..9) of a grid
+	PatternGridMax = 9
+)
+
 /* ----------------------------------------------------------------
  *                         T Y P E S
  *-----------------------------------------------------------------*/
 
-// The size of the square PatternLock grid
+// The size of the PatternLock grid. The original square grids are the
+// enumeration values PatternMode3x3..PatternMode5x5, any other grid of
+// up to 9x9 packs its columns and rows in the high and low nibbles.
 type PatternMode uint8
 
+/* ----------------------------------------------------------------
+ *                  C O N S T R U C T O R S
+ *-----------------------------------------------------------------*/
+
+// the pattern mode for a grid of the given number of columns and rows,
+// both in the range PatternGridMin..PatternGridMax.
+func NewPatternMode(columns, rows int) (PatternMode, error) {
+	if columns < PatternGridMin || columns > PatternGridMax || rows < PatternGridMin || rows > PatternGridMax {
+		return PatternModeNone, fmt.Errorf("unsupported grid %dx%d (%d..%d dots per side)",
+			columns, rows, PatternGridMin, PatternGridMax)
+	}
+	// keep the original enumeration values for the original grids
+	if columns == rows {
+		switch columns {
+		case 3:
+			return PatternMode3x3, nil
+		case 4:
+			return PatternMode4x4, nil
+		case 5:
+			return PatternMode5x5, nil
+		}
+	}
+	return PatternMode(columns<<4 | rows), nil
+}
+
+// strictly convert s (None|CxR like 3x3 or 4x6) to an enumeration value.
+// Unlike Parse() an unrecognized value is reported as an error.
+func ParsePatternMode(s string) (PatternMode, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "none" {
+		return PatternModeNone, nil
+	}
+	colText, rowText, found := strings.Cut(s, "x")
+	if found {
+		columns, errC := strconv.Atoi(colText)
+		rows, errR := strconv.Atoi(rowText)
+		if errC == nil && errR == nil {
+			return NewPatternMode(columns, rows)
+		}
+	}
+	return PatternModeNone, fmt.Errorf("unrecognized pattern mode '%s'", s)
+}
+
 /* ----------------------------------------------------------------
  *                        M E T H O D S
  *-----------------------------------------------------------------*/
 
-// implements fmt.Stringer by returning "None", "3x3", "4x4" or "5x5"
+// implements fmt.Stringer by returning "None" or the grid size as
+// columns x rows like "3x3" or "4x6". Unknown values give "".
 func (pm PatternMode) String() string {
-	var result string
-	switch pm {
-	case PatternMode3x3:
-		result = "3x3"
-	case PatternMode4x4:
-		result = "4x4"
-	case PatternMode5x5:
-		result = "5x5"
-	case PatternModeNone:
-		result = "None"
-	default:
-		result = ""
+	if pm == PatternModeNone {
+		return "None"
 	}
-	return result
+	if !pm.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", pm.Columns(), pm.Rows())
 }
 
-// for a grid size (0|3|4|5) convert to enumeration value
+// for a grid size (0|3..9) convert to the square grid enumeration value
 func (pm PatternMode) Convert(gridSize int) PatternMode {
-	var result PatternMode
-	switch gridSize {
-	case 0:
-		result = PatternModeNone
-	case 3:
-		result = PatternMode3x3
-	case 4:
-		result = PatternMode4x4
-	case 5:
-		result = PatternMode5x5
-	default:
-		result = PatternModeNone
+	if gridSize == 0 {
+		return PatternModeNone
+	}
+	result, err := NewPatternMode(gridSize, gridSize)
+	if err != nil {
 		println("Unsupported gridSize in Convert", gridSize)
 	}
 	return result
 }
 
-// convert s (0/None/0x0|3x3|4x4|5x5) to enumeration value
+// convert s (0/None/0x0|CxR like 3x3 or 4x6) to enumeration value
 func (pm PatternMode) Parse(s string) PatternMode {
-	var result PatternMode
-	switch strings.ToLower(s) {
-	case "0":
-		fallthrough
-	case "0x0":
-		fallthrough
-	case "none":
-		result = PatternModeNone
-	case "3x3":
-		result = PatternMode3x3
-	case "4x4":
-		result = PatternMode4x4
-	case "5x5":
-		result = PatternMode5x5
-	default:
-		result = PatternModeNone
+	if s == "0" || s == "0x0" {
+		return PatternModeNone
+	}
+	result, err := ParsePatternMode(s)
+	if err != nil {
 		println("Unrecognized pattern mode in Parse", s)
 	}
 	return result
 }
 
-// the array width for the current pattern mode value
-func (pm PatternMode) Width() int {
+// whether the value is a usable grid (None is not)
+func (pm PatternMode) IsValid() bool {
+	switch pm {
+	case PatternMode3x3, PatternMode4x4, PatternMode5x5:
+		return true
+	}
+	columns, rows := int(pm>>4), int(pm&0x0f)
+	if columns == rows && columns <= 5 {
+		// only the original enumeration values represent those
+		return false
+	}
+	return columns >= PatternGridMin && columns <= PatternGridMax &&
+		rows >= PatternGridMin && rows <= PatternGridMax
+}
+
+// the number of columns of the grid (A, B, C...)
+func (pm PatternMode) Columns() int {
 	var result int
 	switch pm {
 	case PatternMode3x3:
@@ -102,7 +158,44 @@ func (pm PatternMode) Width() int {
 	case PatternMode5x5:
 		result = 5
 	default:
-		result = 0
+		if pm.IsValid() {
+			result = int(pm >> 4)
+		}
 	}
 	return result
 }
+
+// the number of rows of the grid (1, 2, 3...)
+func (pm PatternMode) Rows() int {
+	var result int
+	switch pm {
+	case PatternMode3x3, PatternMode4x4, PatternMode5x5:
+		result = pm.Columns()
+	default:
+		if pm.IsValid() {
+			result = int(pm & 0x0f)
+		}
+	}
+	return result
+}
+
+// the array width for the current pattern mode value, that is the
+// number of columns. It is the row stride of the dot indices.
+func (pm PatternMode) Width() int {
+	return pm.Columns()
+}
+
+// the array height for the current pattern mode value (rows)
+func (pm PatternMode) Height() int {
+	return pm.Rows()
+}
+
+// the total number of dots in the grid
+func (pm PatternMode) Dots() int {
+	return pm.Columns() * pm.Rows()
+}
+
+// the shortest side of the grid, it is the minimum pattern length
+func (pm PatternMode) minSide() int {
+	return min(pm.Columns(), pm.Rows())
+}
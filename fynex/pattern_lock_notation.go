@@ -0,0 +1,358 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Text notations of a pattern. The parser is lenient: dots can be
+ * separated by dashes, commas, spaces or arrows, given as A1 (any
+ * case) or as numeric indices, and prefixed by the mode (4x4:A1-B2).
+ * The formatter writes the friendly, numeric, keypad & arrow styles.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// column letter and 1-based row like A1-B1-C1 (default)
+	NotationFriendly NotationStyle = iota
+	// the internal 0-based indices like 0,1,2
+	NotationNumeric
+	// the digits of a phone keypad like 123, only for 3x3
+	NotationKeypad
+	// friendly dots joined by arrows like A1 → B1 → C1
+	NotationArrow
+)
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// The text form of a pattern produced by FormatPattern()
+type NotationStyle uint8
+
+// A pattern that could not be parsed. Column is the 1-based position
+// (in characters) of the offending dot in the input text.
+type NotationError struct {
+	Column int
+	Text   string // the offending dot, empty at the end of the input
+	Reason string
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// a dot of the input text and where it starts
+type notationToken struct {
+	text   string
+	column int // 1-based
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// implements the error interface
+func (e *NotationError) Error() string {
+	if e.Text == "" {
+		return fmt.Sprintf("pattern notation error at column %d: %s", e.Column, e.Reason)
+	}
+	return fmt.Sprintf("pattern notation error at column %d '%s': %s", e.Column, e.Text, e.Reason)
+}
+
+// implements fmt.Stringer
+func (ns NotationStyle) String() string {
+	var result string
+	switch ns {
+	case NotationFriendly:
+		result = "Friendly"
+	case NotationNumeric:
+		result = "Numeric"
+	case NotationKeypad:
+		result = "Keypad"
+	case NotationArrow:
+		result = "Arrow"
+	default:
+		result = ""
+	}
+	return result
+}
+
+// the pattern in the given notation style, see FormatPattern()
+func (pi *PatternInfo) Format(style NotationStyle) string {
+	return FormatPattern(pi.mode, pi.pattern, style)
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// given a pattern mode like 3x3 or 4x4 and a sequence of dots,
+// convert the sequence from indices to friendly cartesian coordinates.
+func PatternInfoString(mode PatternMode, sequence []int) string {
+	return FormatPattern(mode, sequence, NotationFriendly)
+}
+
+// Writes the sequence of dots in the notation style. It is empty for
+// an invalid mode, or for NotationKeypad on a grid other than 3x3.
+// Every style can be read back by ParseStringPatternFor().
+func FormatPattern(mode PatternMode, sequence []int, style NotationStyle) string {
+	if !mode.IsValid() {
+		return ""
+	}
+
+	dots := make([]string, len(sequence))
+	for i, index := range sequence {
+		switch style {
+		case NotationNumeric:
+			dots[i] = strconv.Itoa(index)
+		case NotationKeypad:
+			dots[i] = strconv.Itoa(index + 1)
+		default:
+			column, row := index%mode.Columns(), index/mode.Columns()+1
+			dots[i] = fmt.Sprintf("%c%d", rune('A'+column), row)
+		}
+	}
+
+	switch style {
+	case NotationNumeric:
+		return strings.Join(dots, ",")
+	case NotationKeypad:
+		if mode != PatternMode3x3 {
+			return ""
+		}
+		return strings.Join(dots, "")
+	case NotationArrow:
+		return strings.Join(dots, " → ")
+	}
+	return strings.Join(dots, "-")
+}
+
+// Takes a pattern in text form and validates it for the selected
+// pattern mode. The dots may be separated by '-', ',', spaces, '>' or
+// '→' and be written as A1 (any case) or as 0-based indices. On a 3x3
+// grid a single run of keypad digits like 12369 is also accepted. An
+// optional mode prefix like "3x3:" must match the mode. It ensures the
+// dots are in the grid, that no dot follows itself and that the
+// DefaultPatternRules are satisfied. Errors are *NotationError.
+func ParseStringPatternFor(pattern string, mode PatternMode) ([]int, error) {
+	return ParseStringPatternWithRules(pattern, mode, DefaultPatternRules)
+}
+
+// Like ParseStringPatternFor() but checking the given rules instead,
+// nil means DefaultPatternRules.
+func ParseStringPatternWithRules(pattern string, mode PatternMode, rules PatternRule) ([]int, error) {
+	if mode == PatternModeNone {
+		return []int{}, errors.New("cannot parse for pattern mode None")
+	}
+	if !mode.IsValid() {
+		return []int{}, errors.New("invalid pattern mode to ParseStringPattern")
+	}
+
+	prefix, tokens, err := tokenizePattern(pattern)
+	if err != nil {
+		return []int{}, err
+	}
+	if prefix != PatternModeNone && prefix != mode {
+		return []int{}, &NotationError{Column: 1, Text: prefix.String(),
+			Reason: fmt.Sprintf("mode prefix differs from %s", mode)}
+	}
+	return decodeTokens(pattern, tokens, mode, rulesOrDefault(rules))
+}
+
+// Parses a pattern in any notation accepted by ParseStringPatternFor()
+// and identifies its mode. With a mode prefix like "4x5:" that mode is
+// used. Otherwise it is the smallest square grid (3x3 at least) that
+// holds every dot, keypad digits are 3x3. Numeric indices need the
+// prefix because an index is another dot on another grid. Note that a
+// pattern that only uses the upper-left dots of a larger grid looks
+// like a smaller grid: use a prefix or ParseStringPatternFor() to be
+// certain.
+func ParseStringPattern(pattern string) ([]int, PatternMode, error) {
+	prefix, tokens, err := tokenizePattern(pattern)
+	if err != nil {
+		return []int{}, PatternModeNone, err
+	}
+	mode := prefix
+	if mode == PatternModeNone {
+		if mode, err = inferPatternMode(tokens); err != nil {
+			return []int{}, PatternModeNone, err
+		}
+	}
+	sequence, err := decodeTokens(pattern, tokens, mode, DefaultPatternRules)
+	return sequence, mode, err
+}
+
+// Splits the text in dots after removing the optional mode prefix,
+// which is PatternModeNone when absent.
+func tokenizePattern(pattern string) (PatternMode, []notationToken, error) {
+	mode := PatternModeNone
+	runes := []rune(pattern)
+	offset := 0
+	if modeText, _, found := strings.Cut(pattern, patternMODE_SEPARATOR); found {
+		var err error
+		if mode, err = ParsePatternMode(modeText); err != nil {
+			return PatternModeNone, nil, &NotationError{Column: 1, Text: strings.TrimSpace(modeText),
+				Reason: "invalid mode prefix"}
+		}
+		offset = len([]rune(modeText)) + 1
+	}
+
+	tokens := make([]notationToken, 0)
+	var current []rune
+	start := 0
+	for i := offset; i <= len(runes); i++ {
+		if i < len(runes) && !isNotationSeparator(runes[i]) {
+			if current == nil {
+				start = i
+			}
+			current = append(current, unicode.ToUpper(runes[i]))
+			continue
+		}
+		if current != nil {
+			tokens = append(tokens, notationToken{text: string(current), column: start + 1})
+			current = nil
+		}
+	}
+	return mode, tokens, nil
+}
+
+// converts the dots to internal indices and validates them for mode
+func decodeTokens(pattern string, tokens []notationToken, mode PatternMode, rules PatternRule) ([]int, error) {
+	keypad := len(tokens) == 1 && isKeypadRun(tokens[0].text) && mode == PatternMode3x3
+	if keypad {
+		tokens = splitKeypadRun(tokens[0])
+	}
+	expected := fmt.Sprintf("A1..%c%d or 0..%d", rune('A'+mode.Columns()-1), mode.Rows(), mode.Dots()-1)
+	if keypad {
+		expected = "1..9"
+	}
+	result := make([]int, len(tokens))
+	for i, token := range tokens {
+		index, err := decodeDot(token.text, mode, keypad)
+		if err != nil {
+			return []int{}, &NotationError{Column: token.column, Text: token.text,
+				Reason: fmt.Sprintf("%s, expected %s", err, expected)}
+		}
+		result[i] = index
+	}
+
+	// ensure the same dot is not adjacent to itself
+	for i := 1; i < len(result); i++ {
+		if result[i] == result[i-1] {
+			return []int{}, &NotationError{Column: tokens[i].column, Text: tokens[i].text,
+				Reason: "the dot repeats itself"}
+		}
+	}
+	// and that the rules are satisfied, pointing at the offending dot
+	if err := rules.Check(result, mode); err != nil {
+		var ruleErr *PatternRuleError
+		if !errors.As(err, &ruleErr) {
+			return []int{}, err
+		}
+		if ruleErr.Index < 0 || ruleErr.Index >= len(tokens) {
+			return []int{}, &NotationError{Column: len([]rune(pattern)) + 1, Reason: ruleErr.Reason}
+		}
+		token := tokens[ruleErr.Index]
+		return []int{}, &NotationError{Column: token.column, Text: token.text, Reason: ruleErr.Reason}
+	}
+
+	return result, nil
+}
+
+// the internal index of a dot written as A1, as a numeric index or as
+// a keypad digit
+func decodeDot(dot string, mode PatternMode, keypad bool) (int, error) {
+	if keypad {
+		// keypad digits are 1-based, there is no 0
+		if dot < "1" || dot > "9" {
+			return 0, errors.New("not a keypad digit")
+		}
+		return int(dot[0] - '1'), nil
+	}
+	if isDigits(dot) {
+		index, err := strconv.Atoi(dot)
+		if err != nil || index >= mode.Dots() {
+			return 0, errors.New("index outside the grid")
+		}
+		return index, nil
+	}
+
+	if len(dot) != 2 || dot[0] < 'A' || dot[0] > 'I' || dot[1] < '1' || dot[1] > '9' {
+		return 0, errors.New("invalid notation")
+	}
+	// Column names are in ASCII so only 1-byte per letter
+	column, row := int(dot[0]-'A'), int(dot[1]-'0')
+	if column >= mode.Columns() || row > mode.Rows() {
+		return 0, errors.New("dot outside the grid")
+	}
+	// the human-friendly string rows are 1-based
+	return (row-1)*mode.Columns() + column, nil
+}
+
+// The smallest square grid, 3x3 at least, where all the dots fit. A
+// lone run of digits is a keypad pattern on a 3x3 grid. An index fits
+// every grid with enough dots, so it is an error without a mode prefix.
+func inferPatternMode(tokens []notationToken) (PatternMode, error) {
+	if len(tokens) == 1 && isKeypadRun(tokens[0].text) {
+		return PatternMode3x3, nil
+	}
+
+	side := PatternGridMin
+	for _, token := range tokens {
+		dot := token.text
+		switch {
+		case isDigits(dot):
+			return PatternModeNone, &NotationError{Column: token.column, Text: token.text,
+				Reason: "numeric dots need a mode prefix like 3x3:"}
+		case len(dot) == 2 && dot[0] >= 'A' && dot[0] <= 'I' && dot[1] >= '1' && dot[1] <= '9':
+			side = max(side, int(dot[0]-'A')+1, int(dot[1]-'0'))
+		}
+	}
+	return NewPatternMode(side, side)
+}
+
+// whether the rune separates two dots
+func isNotationSeparator(r rune) bool {
+	switch r {
+	case '-', ',', '>', '→':
+		return true
+	}
+	return unicode.IsSpace(r)
+}
+
+// whether the text only has decimal digits
+func isDigits(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// whether the text is several keypad digits written together
+func isKeypadRun(text string) bool {
+	return len(text) > 1 && isDigits(text)
+}
+
+// one dot per keypad digit
+func splitKeypadRun(run notationToken) []notationToken {
+	tokens := make([]notationToken, len(run.text))
+	for i := range run.text {
+		tokens[i] = notationToken{text: run.text[i : i+1], column: run.column + i}
+	}
+	return tokens
+}
@@ -0,0 +1,115 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the pattern notations: the column of a parse error, the
+ * mode inferred without a prefix and the formatter round trip.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestParsePatternErrorColumn(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		rules      PatternRule
+		wantColumn int
+		wantText   string
+	}{
+		{"off the grid", "A1-B1-D1", nil, 7, "D1"},
+		{"invalid dot", "A1-B1-C1-X", nil, 10, "X"},
+		{"repeated dot", "A1-B1-B1-C1", nil, 7, "B1"},
+		{"too short", "A1-B1", nil, 6, ""},
+		{"rule index", "A1-B1-C1-C2-C3", MaxLength(4), 13, "C3"},
+		{"mode prefix", "3x3: a1 , b1 ,z9", nil, 15, "Z9"},
+		{"arrows count as one column", "A1 → B1 → Q1", nil, 11, "Q1"},
+		{"index off the grid", "0,1,9", nil, 5, "9"},
+		{"keypad zero", "12309", nil, 4, "0"},
+		{"other prefix", "4x4:A1-B1-C1", nil, 1, "4x4"},
+		{"invalid prefix", "x:A1-B1-C1", nil, 1, "x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseStringPatternWithRules(tt.pattern, PatternMode3x3, tt.rules)
+			var notationErr *NotationError
+			if !errors.As(err, &notationErr) {
+				t.Fatalf("ParseStringPatternFor(%q) error = %v, want a *NotationError", tt.pattern, err)
+			}
+			if notationErr.Column != tt.wantColumn || notationErr.Text != tt.wantText {
+				t.Errorf("error at column %d %q, want column %d %q", notationErr.Column, notationErr.Text,
+					tt.wantColumn, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestParsePatternInferredMode(t *testing.T) {
+	mode4x5, _ := NewPatternMode(4, 5)
+	tests := []struct {
+		pattern    string
+		wantMode   PatternMode
+		wantDots   []int
+		wantColumn int // of the error, zero when it parses
+	}{
+		{"A1-B1-C1-C2-C3", PatternMode3x3, []int{0, 1, 2, 5, 8}, 0},
+		{"a1 b2 d4 c4", PatternMode4x4, []int{0, 5, 15, 14}, 0},
+		{"A1-E1-E5-A5-C3", PatternMode5x5, []int{0, 4, 24, 20, 12}, 0},
+		{"12369", PatternMode3x3, []int{0, 1, 2, 5, 8}, 0},
+		{"4x5:A1-B1-C1-D5", mode4x5, []int{0, 1, 2, 19}, 0},
+		{"3x3:0,1,2,5,8", PatternMode3x3, []int{0, 1, 2, 5, 8}, 0},
+		{"4x4:0,1,2,5,8", PatternMode4x4, []int{0, 1, 2, 5, 8}, 0},
+		// an index is another dot on every grid
+		{"0,1,2,5,8", PatternModeNone, nil, 1},
+		{"A1-B1-5-C1", PatternModeNone, nil, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			dots, mode, err := ParseStringPattern(tt.pattern)
+			if tt.wantColumn != 0 {
+				var notationErr *NotationError
+				if !errors.As(err, &notationErr) || notationErr.Column != tt.wantColumn {
+					t.Fatalf("ParseStringPattern() error = %v, want one at column %d", err, tt.wantColumn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStringPattern() error = %v", err)
+			}
+			if mode != tt.wantMode || !slices.Equal(dots, tt.wantDots) {
+				t.Errorf("ParseStringPattern() = %v %s, want %v %s", dots, mode, tt.wantDots, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestFormatPatternRoundTrip(t *testing.T) {
+	styles := []NotationStyle{NotationFriendly, NotationNumeric, NotationKeypad, NotationArrow}
+	patterns := []*PatternInfo{
+		mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3),
+		mustPattern(t, []int{15, 10, 5, 0, 1, 2, 3}, PatternMode4x4),
+	}
+	for _, pattern := range patterns {
+		for _, style := range styles {
+			text := pattern.Format(style)
+			if text == "" {
+				if style != NotationKeypad || pattern.Mode() == PatternMode3x3 {
+					t.Errorf("%s of a %s pattern is empty", style, pattern.Mode())
+				}
+				continue
+			}
+			dots, err := ParseStringPatternFor(text, pattern.Mode())
+			if err != nil || !slices.Equal(dots, pattern.Pattern()) {
+				t.Errorf("%s %q read back as %v, %v", style, text, dots, err)
+			}
+		}
+	}
+}
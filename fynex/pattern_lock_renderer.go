@@ -2,12 +2,15 @@
  *					Copyright(C)2026 Lord of Scripts
  *						All Rights Reserved
  * -----------------------------------------------------------------
- * A custom renderer for the custom PatternLock widget.
+ * A custom renderer for the custom PatternLock widget. The canvas
+ * objects are created once and updated in place, a drag generates
+ * many refreshes per second.
  ********************************************************************/
 package fynex
 
 import (
 	"image/color"
+	"slices"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -18,11 +21,11 @@ import (
  *                       G L O B A L S
  * -----------------------------------------------------------------*/
 
-const (
-	// a fixed offset to ensure both the custom widget and renderer
-	// properly identify where the user touches is where the dots are.
-	statusLABEL_OFFSET = 40
-)
+// height of the live strength meter bar (design state)
+const strengthBAR_HEIGHT = 6
+
+// opacity of the hit areas shown with ShowHitArea
+const hitAREA_ALPHA = 0.25
 
 /* ----------------------------------------------------------------
  *                     I N T E R F A C E S
@@ -39,6 +42,16 @@ type patternRenderer struct {
 	statusLabel *widget.Label
 	background  *canvas.Image
 	fadeOverlay *canvas.Rectangle
+	meterTrack  *canvas.Rectangle
+	meterBar    *canvas.Rectangle
+	meterText   *canvas.Text
+	cursorRing  *canvas.Circle
+	hoverRing   *canvas.Circle   // hit area of the dot under the pointer
+	hitAreas    []*canvas.Circle // one per grid dot, see ShowHitArea
+	dots        []*canvas.Circle // one per grid dot, created by Layout()
+	images      []*canvas.Image  // one per grid dot, nil unless styled
+	lines       []*canvas.Line   // pool of path segments, grows as needed
+	dragLine    *canvas.Line     // from the last dot to the pointer
 	objects     []fyne.CanvasObject
 	lastSize    fyne.Size // the last size used by Layout()
 }
@@ -48,9 +61,9 @@ type patternRenderer struct {
  * -----------------------------------------------------------------*/
 
 func newPatternRenderer(p *PatternLock) *patternRenderer {
-	// Create a rectangle to act as the "fade" layer
-	// Black with alpha 180 creates a dark fade; use White for a light fade
-	fade := canvas.NewRectangle(color.NRGBA{R: 0, G: 0, B: 0, A: 150})
+	// Create a rectangle to act as the "fade" layer, its color comes
+	// from the style (the theme background, partly transparent)
+	fade := canvas.NewRectangle(color.Transparent)
 
 	var img *canvas.Image = nil
 	if p.backgroundRsrc != nil {
@@ -61,12 +74,22 @@ func newPatternRenderer(p *PatternLock) *patternRenderer {
 
 	r := &patternRenderer{
 		p: p,
-		statusLabel: widget.NewLabelWithStyle("Design your unlock pattern",
+		statusLabel: widget.NewLabelWithStyle(p.message(MsgPatternPrompt),
 			fyne.TextAlignCenter,
 			fyne.TextStyle{Bold: true}),
 		background:  img,
 		fadeOverlay: fade,
+		meterTrack:  canvas.NewRectangle(color.NRGBA{R: 255, G: 255, B: 255, A: 40}),
+		meterBar:    canvas.NewRectangle(color.Transparent),
+		meterText:   canvas.NewText("", color.White),
+		cursorRing:  canvas.NewCircle(color.Transparent),
+		hoverRing:   canvas.NewCircle(color.Transparent),
+		dragLine:    canvas.NewLine(color.Transparent),
 	}
+	r.cursorRing.StrokeWidth = 2
+	r.hoverRing.StrokeWidth = hoverRING_WIDTH
+	r.meterText.TextSize = 12
+	r.meterText.Alignment = fyne.TextAlignTrailing
 	// ensure the first Refresh() has a valid size greater than 0,0
 	r.lastSize = r.MinSize()
 	return r
@@ -89,12 +112,16 @@ func (r *patternRenderer) Layout(size fyne.Size) {
 	r.fadeOverlay.Resize(size)
 	r.fadeOverlay.Move(fyne.NewPos(0, 0))
 
-	r.statusLabel.Resize(fyne.NewSize(size.Width, 30))
-	r.statusLabel.Move(fyne.NewPos(0, 5))
+	// the status label, dots and lines follow the new size
+	if r.createDots() {
+		r.objects = nil
+	}
+	r.Refresh()
 }
 
+// 300 pixels wide with square cells plus the status area
 func (r *patternRenderer) MinSize() fyne.Size {
-	return fyne.NewSize(300, 340)
+	return r.p.minSize()
 }
 
 func (r *patternRenderer) Destroy() {}
@@ -104,102 +131,329 @@ func (r *patternRenderer) Objects() []fyne.CanvasObject {
 }
 
 func (r *patternRenderer) Refresh() {
+	// the objects slice is only rebuilt when objects are added or removed
+	rebuild := r.objects == nil
+	state := r.p.renderState()
+
 	// 1. Synchronize the background canvas object with the widget's resource
-	if r.p.backgroundRsrc != nil {
+	if state.background != nil {
 		if r.background == nil {
 			// Create the canvas object if it didn't exist at startup
-			r.background = canvas.NewImageFromResource(r.p.backgroundRsrc)
+			r.background = canvas.NewImageFromResource(state.background)
 			r.background.FillMode = canvas.ImageFillStretch
 			// Manually resize and move because Layout() won't be called automatically
 			r.background.Resize(r.lastSize)
 			r.background.Move(fyne.NewPos(0, 0))
-		} else if r.background.Resource != r.p.backgroundRsrc {
+			r.background.Refresh()
+			rebuild = true
+		} else if r.background.Resource != state.background {
 			// Update the resource if it changed and trigger an image refresh
-			r.background.Resource = r.p.backgroundRsrc
+			r.background.Resource = state.background
 			r.background.Refresh()
 		}
-	} else {
+	} else if r.background != nil {
 		r.background = nil
+		rebuild = true
 	}
 
-	// 2. Update the status label text
-	r.statusLabel.SetText(r.p.Status)
+	style := state.style
+	if r.fadeOverlay.FillColor != style.OverlayColor {
+		r.fadeOverlay.FillColor = style.OverlayColor
+		r.fadeOverlay.Refresh()
+	}
 
-	// 3. Rebuild the objects slice
-	// We must rebuild this list so Fyne knows to include the background if it was previously nil
-	r.objects = []fyne.CanvasObject{}
-	if r.background != nil {
-		r.objects = append(r.objects, r.background)
+	// 2. Update the status label, unless hidden or shown elsewhere
+	geometry := r.p.geometry(r.lastSize)
+	if geometry.statusSize.IsZero() {
+		r.statusLabel.Hide()
+	} else {
+		r.statusLabel.Move(geometry.status)
+		r.statusLabel.Resize(geometry.statusSize)
+		r.statusLabel.Show()
+		if r.statusLabel.Text != state.status {
+			r.statusLabel.SetText(state.status)
+		}
 	}
-	r.objects = append(r.objects, r.fadeOverlay)
-	r.objects = append(r.objects, r.statusLabel)
 
-	// 4. Drawing logic for lines and dots
-	renderSize := r.lastSize
-	gridAreaTop := float32(40)
-	gridHeight := renderSize.Height - gridAreaTop
-	colWidth := renderSize.Width / float32(r.p.GridSize)
-	rowHeight := gridHeight / float32(r.p.GridSize)
-	dotRadius := fyne.Min(colWidth, rowHeight) / 8
+	// 3. Drawing logic for lines and dots, with the same geometry that
+	// is used to hit-test the pointer
+	dotRadius := geometry.dotRadius(style.DotRadiusRatio)
+	hitRadius := geometry.hitRadius(style.HitRadiusRatio)
+	center := geometry.dotCenter
+
+	// validation feedback and playback fade
+	lineColor, effect, stealth := state.lineColor, state.effect, state.stealth
+	pathColor := fadeColor(lineColor, effect.alpha)
+	shift := fyne.NewDelta(effect.offset, 0)
+
+	// Lines between the visited dots
+	segments := 0
+	if stealth == StealthOff {
+		segments = max(len(state.sequence)-1, 0)
+	}
+	for len(r.lines) < segments {
+		line := canvas.NewLine(pathColor)
+		r.lines = append(r.lines, line)
+		rebuild = true
+	}
+	for i, line := range r.lines {
+		if i >= segments {
+			line.Hide()
+			continue
+		}
+		updateLine(line, center(state.sequence[i]).Add(shift), center(state.sequence[i+1]).Add(shift), pathColor, style.LineWidth)
+	}
 
-	lineColor := r.p.selectedColor
-	if r.p.designing {
-		lineColor = color.NRGBA{R: 0x9d, G: 0, B: 0xff, A: 255}
+	// Active drag line
+	if stealth == StealthOff && state.active && len(state.sequence) > 0 {
+		last := center(state.sequence[len(state.sequence)-1]).Add(shift)
+		updateLine(r.dragLine, last, state.hover, pathColor, style.DragLineWidth)
+	} else {
+		r.dragLine.Hide()
 	}
 
-	// Draw lines
-	if len(r.p.Sequence) > 1 {
-		for i := 0; i < len(r.p.Sequence)-1; i++ {
-			r.objects = append(r.objects, r.createLine(r.p.Sequence[i], r.p.Sequence[i+1], colWidth, rowHeight, gridAreaTop, lineColor))
+	// Dots and their hit areas, see createDots()
+	for i, area := range r.hitAreas {
+		if !style.ShowHitArea {
+			area.Hide()
+			continue
 		}
+		updateCircle(area, center(i), hitRadius, fadeColor(style.HoverColor, hitAREA_ALPHA))
+		area.Show()
+	}
+	// approached dots grow while drawing, unless it would give the path away
+	approaching := state.active && stealth == StealthOff
+	if r.refreshImages(style) {
+		rebuild = true
+	}
+	for i, dot := range r.dots {
+		pos := center(i)
+		var highlight color.Color // nil when the dot is not highlighted
+		alpha, scale := float32(1), float32(1)
+		if slices.Contains(state.sequence, i) {
+			switch stealth {
+			case StealthOff:
+				// fading the path turns the dot back to idle
+				highlight, alpha, scale = lineColor, effect.alpha, effect.scale
+				pos = pos.Add(shift)
+			case StealthNeutral:
+				// the feedback color shows the result, never the order
+				highlight, alpha, scale = style.NeutralColor, effect.alpha, effect.scale
+				if effect.color != nil {
+					highlight = effect.color
+				}
+				pos = pos.Add(shift)
+			}
+		}
+		if stealth == StealthPulse && state.pulsing && i == state.pulseIndex {
+			highlight, alpha, scale = style.NeutralColor, 1, state.pulseScale
+		}
+		if approaching && highlight == nil {
+			scale = proximityScale(pos, state.hover, hitRadius)
+		}
+		if img := r.images[i]; img != nil {
+			// the image stands for the dot, a halo shows it was visited
+			haloColor := color.Color(color.Transparent)
+			if highlight != nil {
+				haloColor = fadeColor(highlight, alpha)
+			}
+			updateCircle(dot, pos, hitRadius*scale, haloColor)
+			updateImage(img, pos, hitRadius*dotIMAGE_FILL*scale)
+			continue
+		}
+		dotColor := style.IdleDotColor
+		if highlight != nil {
+			dotColor = blendColor(style.IdleDotColor, highlight, alpha)
+		}
+		updateCircle(dot, pos, dotRadius*scale, dotColor)
 	}
 
-	// Draw active drag line
-	if r.p.active && len(r.p.Sequence) > 0 {
-		lastIdx := r.p.Sequence[len(r.p.Sequence)-1]
-		lastX := colWidth*float32(lastIdx%r.p.GridSize) + colWidth/2
-		lastY := rowHeight*float32(lastIdx/r.p.GridSize) + rowHeight/2 + gridAreaTop
+	// Keyboard cursor ring, it would trace the path in stealth mode
+	if state.focused && stealth == StealthOff {
+		ringRadius := dotRadius * 2
+		r.cursorRing.StrokeColor = lineColor
+		r.cursorRing.Resize(fyne.NewSize(ringRadius*2, ringRadius*2))
+		r.cursorRing.Move(center(state.cursor).SubtractXY(ringRadius, ringRadius))
+		r.cursorRing.Show()
+		r.cursorRing.Refresh()
+	} else {
+		r.cursorRing.Hide()
+	}
 
-		line := canvas.NewLine(lineColor)
-		line.Position1 = fyne.NewPos(lastX, lastY)
-		line.Position2 = r.p.hover
-		line.StrokeWidth = 4
-		r.objects = append(r.objects, line)
+	// Hover ring, the hit area of the dot under the pointer
+	if state.hoverDot >= 0 && state.hoverDot < len(r.dots) {
+		r.hoverRing.StrokeColor = style.HoverColor
+		r.hoverRing.Resize(fyne.NewSquareSize(hitRadius * 2))
+		r.hoverRing.Move(center(state.hoverDot).SubtractXY(hitRadius, hitRadius))
+		r.hoverRing.Show()
+		r.hoverRing.Refresh()
+	} else {
+		r.hoverRing.Hide()
 	}
 
-	// Draw dots
-	for i := 0; i < r.p.GridSize*r.p.GridSize; i++ {
-		x := colWidth*float32(i%r.p.GridSize) + colWidth/2
-		y := rowHeight*float32(i/r.p.GridSize) + rowHeight/2 + gridAreaTop
+	// Live strength meter while designing
+	if state.meter && len(state.sequence) > 0 {
+		r.refreshStrengthMeter(geometry, state.sequence)
+		r.meterTrack.Show()
+		r.meterBar.Show()
+		r.meterText.Show()
+	} else {
+		r.meterTrack.Hide()
+		r.meterBar.Hide()
+		r.meterText.Hide()
+	}
 
-		dotColor := color.Color(color.Gray{Y: 150})
-		for _, seqID := range r.p.Sequence {
-			if seqID == i {
-				dotColor = lineColor
-				break
-			}
-		}
+	if rebuild {
+		r.rebuildObjects()
+	}
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
 
-		dot := canvas.NewCircle(dotColor)
-		dot.Resize(fyne.NewSize(dotRadius*2, dotRadius*2))
-		dot.Move(fyne.NewPos(x-dotRadius, y-dotRadius))
-		r.objects = append(r.objects, dot)
+// Creates the dots and their hit areas on the first layout, the grid
+// never changes afterwards. It reports whether they were created.
+func (r *patternRenderer) createDots() bool {
+	count := r.p.gridMode.Columns() * r.p.gridMode.Rows()
+	if len(r.dots) == count {
+		return false
 	}
+	r.dots = make([]*canvas.Circle, count)
+	r.hitAreas = make([]*canvas.Circle, count)
+	for i := range r.dots {
+		r.dots[i] = canvas.NewCircle(color.Transparent)
+		r.hitAreas[i] = canvas.NewCircle(color.Transparent)
+	}
+	return true
+}
 
-	// Canvas Refresh to ensure the background is painted
+// Lists the canvas objects from back to front. Hidden objects stay in
+// the list so that it only changes when objects are created.
+func (r *patternRenderer) rebuildObjects() {
+	objects := make([]fyne.CanvasObject, 0, len(r.lines)+3*len(r.dots)+10)
 	if r.background != nil {
-		r.background.Refresh()
+		objects = append(objects, r.background)
+	}
+	objects = append(objects, r.fadeOverlay, r.statusLabel)
+	for _, area := range r.hitAreas {
+		objects = append(objects, area)
+	}
+	for _, line := range r.lines {
+		objects = append(objects, line)
+	}
+	objects = append(objects, r.dragLine)
+	for _, dot := range r.dots {
+		objects = append(objects, dot)
+	}
+	for _, img := range r.images {
+		if img != nil {
+			objects = append(objects, img)
+		}
+	}
+	objects = append(objects, r.hoverRing, r.cursorRing, r.meterTrack, r.meterBar, r.meterText)
+	r.objects = objects
+}
+
+// Keeps one image per styled dot in sync with the style. It returns
+// whether images were created or removed.
+func (r *patternRenderer) refreshImages(style PatternLockStyle) bool {
+	changed := false
+	if len(r.images) != len(r.dots) {
+		r.images = make([]*canvas.Image, len(r.dots))
+		changed = true
+	}
+	for i, img := range r.images {
+		res := style.dotImage(i)
+		switch {
+		case res == nil && img != nil:
+			r.images[i] = nil
+			changed = true
+		case res != nil && img == nil:
+			img = canvas.NewImageFromResource(res)
+			img.FillMode = canvas.ImageFillContain
+			r.images[i] = img
+			changed = true
+		case res != nil && img.Resource != res:
+			img.Resource = res
+			img.Refresh()
+		}
+	}
+	return changed
+}
+
+// size, position and color the strength meter for the current sequence
+func (r *patternRenderer) refreshStrengthMeter(geometry patternGeometry, sequence []int) {
+	strength := AnalyzePattern(sequence, r.p.gridMode)
+	var barColor color.Color
+	switch strength.Level {
+	case StrengthStrong:
+		barColor = color.NRGBA{R: 0, G: 200, B: 0, A: 255}
+	case StrengthGood:
+		barColor = color.NRGBA{R: 160, G: 220, B: 0, A: 255}
+	case StrengthFair:
+		barColor = color.NRGBA{R: 255, G: 170, B: 0, A: 255}
+	default:
+		barColor = color.NRGBA{R: 220, G: 0, B: 0, A: 255}
 	}
+
+	// at the bottom of the grid
+	width := geometry.gridSize.Width
+	top := geometry.grid.Y + geometry.gridSize.Height - strengthBAR_HEIGHT
+	r.meterTrack.Move(fyne.NewPos(0, top))
+	r.meterTrack.Resize(fyne.NewSize(width, strengthBAR_HEIGHT))
+	r.meterBar.FillColor = barColor
+	r.meterBar.Move(fyne.NewPos(0, top))
+	r.meterBar.Resize(fyne.NewSize(width*float32(max(strength.Score, 2))/100, strengthBAR_HEIGHT))
+	r.meterBar.Refresh()
+
+	r.meterText.Text = r.p.message(strength.Level.messageID())
+	r.meterText.Color = barColor
+	textSize := r.meterText.MinSize()
+	r.meterText.Move(fyne.NewPos(width-textSize.Width-4, top-textSize.Height))
+	r.meterText.Resize(textSize)
+	r.meterText.Refresh()
 }
 
 /* -----------------------------------------------------------------
- *                  P R I V A T E    M E T H O D S
+ *                       F U N C T I O N S
  * -----------------------------------------------------------------*/
 
-func (r *patternRenderer) createLine(idx1, idx2 int, w, h, top float32, clr color.Color) *canvas.Line {
-	l := canvas.NewLine(clr)
-	l.StrokeWidth = 6
-	l.Position1 = fyne.NewPos(w*float32(idx1%r.p.GridSize)+w/2, h*float32(idx1/r.p.GridSize)+h/2+top)
-	l.Position2 = fyne.NewPos(w*float32(idx2%r.p.GridSize)+w/2, h*float32(idx2/r.p.GridSize)+h/2+top)
-	return l
+// moves, colors and sizes a line, it is only refreshed when something
+// changed
+func updateLine(line *canvas.Line, from, to fyne.Position, clr color.Color, width float32) {
+	if line.Visible() && line.Position1 == from && line.Position2 == to && line.StrokeColor == clr && line.StrokeWidth == width {
+		return
+	}
+	line.Position1 = from
+	line.Position2 = to
+	line.StrokeColor = clr
+	line.StrokeWidth = width
+	line.Show()
+	line.Refresh()
+}
+
+// centers, sizes and colors a dot, it is only refreshed when something
+// changed
+func updateCircle(dot *canvas.Circle, center fyne.Position, radius float32, clr color.Color) {
+	size := fyne.NewSquareSize(radius * 2)
+	pos := center.SubtractXY(radius, radius)
+	if dot.Position() == pos && dot.Size() == size && dot.FillColor == clr {
+		return
+	}
+	dot.FillColor = clr
+	dot.Resize(size)
+	dot.Move(pos)
+	dot.Refresh()
+}
+
+// centers and sizes a dot image, it is only refreshed when it moved
+func updateImage(img *canvas.Image, center fyne.Position, radius float32) {
+	size := fyne.NewSquareSize(radius * 2)
+	pos := center.SubtractXY(radius, radius)
+	if img.Position() == pos && img.Size() == size {
+		return
+	}
+	img.Resize(size)
+	img.Move(pos)
 }
@@ -0,0 +1,126 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Benchmark of the PatternLock renderer during a drag: every drag
+ * event hit-tests the pointer and refreshes the renderer, so this is
+ * the hot path of the widget. Also tests that the style and geometry
+ * it caches follow the theme, the style and the size.
+ ********************************************************************/
+package fynex
+
+import (
+	"image/color"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestPatternLockCaches(t *testing.T) {
+	app := test.NewTempApp(t)
+	app.Settings().SetTheme(theme.DarkTheme())
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	w := test.NewTempWindow(t, p)
+	w.Resize(fyne.NewSize(300, 360))
+
+	dark := p.Style().OverlayColor
+	app.Settings().SetTheme(theme.LightTheme())
+	if p.Style().OverlayColor == dark {
+		t.Error("the style did not follow the new theme")
+	}
+
+	p.SetStyle(PatternLockStyle{LineWidth: 11})
+	if got := p.Style().LineWidth; got != 11 {
+		t.Errorf("LineWidth = %g after SetStyle, want 11", got)
+	}
+
+	small, large := p.geometry(fyne.NewSize(300, 360)), p.geometry(fyne.NewSize(600, 720))
+	if large.cell.Width != 2*small.cell.Width {
+		t.Errorf("cell width %g at twice the size, want %g", large.cell.Width, 2*small.cell.Width)
+	}
+	p.SetStatusPlacement(StatusHidden)
+	if hidden := p.geometry(fyne.NewSize(600, 720)); hidden.gridSize.Height != 720 {
+		t.Errorf("grid height %g without a status, want 720", hidden.gridSize.Height)
+	}
+}
+
+func TestSelectedColorAfterRender(t *testing.T) {
+	test.NewTempApp(t)
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	w := test.NewTempWindow(t, p)
+	w.Resize(fyne.NewSize(300, 360))
+	r := test.TempWidgetRenderer(t, p).(*patternRenderer)
+	primary := p.Style().SelectedColor
+
+	// a path being drawn, its first segment shows the selected color
+	p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: p.DotCenter(0)}})
+	p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: p.DotCenter(1)}})
+	lineColor := func() color.Color {
+		r.Refresh()
+		return r.lines[0].StrokeColor
+	}
+
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	p.SetSelectedColor(red)
+	if got := p.Style().SelectedColor; !sameColor(got, red) {
+		t.Errorf("SelectedColor = %v after SetSelectedColor, want %v", got, red)
+	}
+	if got := lineColor(); !sameColor(got, red) {
+		t.Errorf("the path is drawn in %v, want %v", got, red)
+	}
+
+	p.ResetColor()
+	if got := lineColor(); !sameColor(got, primary) {
+		t.Errorf("the path is drawn in %v after ResetColor, want %v", got, primary)
+	}
+}
+
+// One op is one drag event over a 5x5 grid. The drag snakes through
+// every dot with three pointer positions per dot, then it is released
+// and starts over.
+func BenchmarkPatternRendererRefresh(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	test.NewTempApp(b)
+	p := NewPatternLockFor(PatternMode5x5, nil)
+	w := test.NewTempWindow(b, p)
+	w.Resize(fyne.NewSize(500, 560))
+
+	path := make([]fyne.Position, 0, 3*25)
+	for row := range 5 {
+		for column := range 5 {
+			if row%2 == 1 {
+				column = 4 - column
+			}
+			center := p.DotCenter(row*5 + column)
+			path = append(path, center.SubtractXY(20, 0), center, center.AddXY(20, 0))
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		at := i % len(path)
+		if at == 0 && i > 0 {
+			p.DragEnd()
+		}
+		p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: path[at]}})
+	}
+}
+
+// whether both colors have the same RGBA values
+func sameColor(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
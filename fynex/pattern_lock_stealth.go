@@ -0,0 +1,115 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Stealth mode for the PatternLock widget. It hides the drawn path
+ * from anyone looking over the user's shoulder, either completely or
+ * by showing only what does not reveal the order of the dots.
+ ********************************************************************/
+package fynex
+
+import (
+	"math"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// the path is drawn as usual
+	StealthOff StealthMode = iota
+	// nothing is shown while drawing
+	StealthHidden
+	// every dot briefly pulses when hit, no lines and no highlight
+	StealthPulse
+	// the visited dots are highlighted in a neutral color, no lines
+	StealthNeutral
+)
+
+// duration of the pulse of a dot hit in StealthPulse mode
+const pulseDURATION = 250 * time.Millisecond
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// How much of the drawn path is visible
+type StealthMode uint8
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// implements fmt.Stringer
+func (sm StealthMode) String() string {
+	var result string
+	switch sm {
+	case StealthOff:
+		result = "Off"
+	case StealthHidden:
+		result = "Hidden"
+	case StealthPulse:
+		result = "Pulse"
+	case StealthNeutral:
+		result = "Neutral"
+	default:
+		result = ""
+	}
+	return result
+}
+
+// Hides the drawn path, for tap, drag and keyboard input alike: the
+// keyboard cursor has no ring and the status only counts the dots. The
+// validation feedback only shows what the mode allows. A pattern shown
+// with Play() is always visible.
+func (p *PatternLock) SetStealthMode(mode StealthMode) *PatternLock {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.stealth = mode
+	return p
+}
+
+// the current stealth mode
+func (p *PatternLock) Stealth() StealthMode {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.stealth
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// briefly grows the dot that was just hit (StealthPulse mode)
+func (p *PatternLock) pulseDot(id int) {
+	if p.pulse != nil {
+		p.pulse.Stop()
+		p.pulse = nil
+	}
+	if fyne.CurrentApp() == nil {
+		return
+	}
+
+	var anim *fyne.Animation
+	anim = fyne.NewAnimation(pulseDURATION, func(progress float32) {
+		if p.pulse != anim {
+			return
+		}
+		p.pulseScale = 1 + float32(math.Sin(float64(progress)*math.Pi)*pulseGROWTH)
+		if progress >= 1 {
+			p.pulse = nil
+			p.pulseScale = 1
+		}
+		p.Refresh()
+	})
+	p.pulse = anim
+	p.pulseIndex = id
+	p.pulseScale = 1
+	anim.Start()
+}
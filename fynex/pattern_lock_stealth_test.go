@@ -0,0 +1,82 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the stealth mode of the PatternLock: what the renderer
+ * shows of a path being drawn, and what the status tells about the
+ * keyboard cursor, in each mode.
+ ********************************************************************/
+package fynex
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestStealthRendering(t *testing.T) {
+	tests := []struct {
+		mode      StealthMode
+		lines     int  // visible segments of the path 0-1-2
+		dragLine  bool // to the pointer
+		cursor    bool // the keyboard cursor ring
+		highlight bool // the visited dots stand out
+	}{
+		{StealthOff, 2, true, true, true},
+		{StealthHidden, 0, false, false, false},
+		{StealthPulse, 0, false, false, false},
+		{StealthNeutral, 0, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			test.NewTempApp(t)
+			p := NewPatternLockFor(PatternMode3x3, nil).SetStealthMode(tt.mode)
+			w := test.NewTempWindow(t, p)
+			w.Resize(fyne.NewSize(300, 360))
+			r := test.TempWidgetRenderer(t, p).(*patternRenderer)
+			p.FocusGained()
+			for _, id := range []int{0, 1, 2} {
+				p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: p.DotCenter(id)}})
+			}
+			p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: p.DotCenter(5).SubtractXY(0, 40)}})
+			r.Refresh()
+
+			lines := 0
+			for _, line := range r.lines {
+				if line.Visible() {
+					lines++
+				}
+			}
+			if lines != tt.lines {
+				t.Errorf("%d path segments shown, want %d", lines, tt.lines)
+			}
+			if r.dragLine.Visible() != tt.dragLine {
+				t.Errorf("drag line shown %t, want %t", r.dragLine.Visible(), tt.dragLine)
+			}
+			if r.cursorRing.Visible() != tt.cursor {
+				t.Errorf("cursor ring shown %t, want %t", r.cursorRing.Visible(), tt.cursor)
+			}
+			idle := p.Style().IdleDotColor
+			for _, id := range []int{0, 1, 2} {
+				if highlighted := !sameColor(r.dots[id].FillColor, idle); highlighted != tt.highlight {
+					t.Errorf("visited dot %d highlighted %t, want %t", id, highlighted, tt.highlight)
+				}
+			}
+			if !sameColor(r.dots[4].FillColor, idle) {
+				t.Error("a dot that was not visited is highlighted")
+			}
+
+			// the keyboard cursor is only read aloud when it is visible
+			p.moveCursor(1, 1)
+			if named := strings.Contains(p.Status, p.getPos(p.cursor)); named != tt.cursor {
+				t.Errorf("status %q names the cursor dot %t, want %t", p.Status, named, tt.cursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Visual style of the PatternLock widget: colors, stroke widths, dot
+ * size and optional images for the dots. The defaults come from the
+ * current fyne.Theme and follow its light or dark variant.
+ ********************************************************************/
+package fynex
+
+import (
+	"image/color"
+	"reflect"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// stroke width of the lines between the visited dots
+	DefaultLineWidth = 6
+	// stroke width of the line from the last dot to the pointer
+	DefaultDragLineWidth = 4
+	// the dot radius as a fraction of the cell size
+	DefaultDotRadiusRatio = 1.0 / 8
+	// the hit radius as a fraction of the cell size
+	DefaultHitRadiusRatio = 1.0 / 3
+	// a larger hit radius would reach into the next cell
+	maxHIT_RADIUS_RATIO = 1.0 / 2
+	// the opacity of the layer that darkens (or lightens) the background
+	defaultOVERLAY_ALPHA = 150
+	// the part of the hit area covered by a dot image
+	dotIMAGE_FILL = 0.8
+)
+
+// the color of the lines and visited dots in design state
+var designColor = color.NRGBA{R: 0x9d, G: 0, B: 0xff, A: 255} // purple
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// The look of a PatternLock. A zero field takes its default from the
+// current theme, so a style only needs the fields it changes.
+type PatternLockStyle struct {
+	IdleDotColor   color.Color // dots that were not visited
+	SelectedColor  color.Color // lines and visited dots
+	DesignColor    color.Color // lines and visited dots in design state
+	NeutralColor   color.Color // visited dots in StealthNeutral, the pulse
+	WrongColor     color.Color // feedback of a wrong pattern
+	GrantedColor   color.Color // feedback of a granted pattern
+	OverlayColor   color.Color // layer between the background and the grid
+	LineWidth      float32     // lines between the visited dots
+	DragLineWidth  float32     // line from the last dot to the pointer
+	DotRadiusRatio float32     // the dot radius as a fraction of the cell
+	HoverColor     color.Color // ring around the dot under the pointer
+	// The pointer hits a dot within this fraction of the cell from its
+	// center, 1/2 at most. The hover ring shows it.
+	HitRadiusRatio float32
+	// shades the hit area of every dot, e.g. to tune HitRadiusRatio
+	ShowHitArea bool
+	// Optional images of the dots (internal index), e.g. rune stones. A
+	// dot without an image is drawn as a circle. A visited dot with an
+	// image gets a halo in the selected color.
+	DotImages []fyne.Resource
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// The style with the theme defaults and the height of a status area
+// shown inside the widget. Both depend on the theme, so they are kept
+// until the theme, its variant or the style change.
+type resolvedStyle struct {
+	theme        fyne.Theme
+	variant      fyne.ThemeVariant
+	style        PatternLockStyle
+	statusHeight float32
+	valid        bool
+}
+
+/* -----------------------------------------------------------------
+ *                  C O N S T R U C T O R S
+ * -----------------------------------------------------------------*/
+
+// (ctor) the default style for the theme and its light or dark variant
+func DefaultPatternLockStyle(th fyne.Theme, variant fyne.ThemeVariant) PatternLockStyle {
+	overlay := color.NRGBAModel.Convert(th.Color(theme.ColorNameBackground, variant)).(color.NRGBA)
+	overlay.A = defaultOVERLAY_ALPHA
+	return PatternLockStyle{
+		IdleDotColor:   th.Color(theme.ColorNamePlaceHolder, variant),
+		SelectedColor:  th.Color(theme.ColorNamePrimary, variant),
+		DesignColor:    designColor,
+		NeutralColor:   th.Color(theme.ColorNameForeground, variant),
+		WrongColor:     th.Color(theme.ColorNameError, variant),
+		GrantedColor:   th.Color(theme.ColorNameSuccess, variant),
+		OverlayColor:   overlay,
+		LineWidth:      DefaultLineWidth,
+		DragLineWidth:  DefaultDragLineWidth,
+		DotRadiusRatio: DefaultDotRadiusRatio,
+		HoverColor:     th.Color(theme.ColorNameFocus, variant),
+		HitRadiusRatio: DefaultHitRadiusRatio,
+	}
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// Sets the look of the widget. Zero fields keep the theme defaults, so
+// PatternLockStyle{} goes back to the default look.
+func (p *PatternLock) SetStyle(style PatternLockStyle) *PatternLock {
+	p.mux.Lock()
+	p.style = style
+	p.style.DotImages = append([]fyne.Resource(nil), style.DotImages...)
+	p.resolved.valid = false
+	p.mux.Unlock()
+
+	p.refreshUI()
+	return p
+}
+
+// the style in use, with the theme defaults filled in
+func (p *PatternLock) Style() PatternLockStyle {
+	th := p.Theme()
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.resolveLocked(th, themeVariant()).style
+}
+
+// the style with every zero field taken from the defaults
+func (s PatternLockStyle) withDefaults(defaults PatternLockStyle) PatternLockStyle {
+	if s.IdleDotColor == nil {
+		s.IdleDotColor = defaults.IdleDotColor
+	}
+	if s.SelectedColor == nil {
+		s.SelectedColor = defaults.SelectedColor
+	}
+	if s.DesignColor == nil {
+		s.DesignColor = defaults.DesignColor
+	}
+	if s.NeutralColor == nil {
+		s.NeutralColor = defaults.NeutralColor
+	}
+	if s.WrongColor == nil {
+		s.WrongColor = defaults.WrongColor
+	}
+	if s.GrantedColor == nil {
+		s.GrantedColor = defaults.GrantedColor
+	}
+	if s.OverlayColor == nil {
+		s.OverlayColor = defaults.OverlayColor
+	}
+	if s.LineWidth <= 0 {
+		s.LineWidth = defaults.LineWidth
+	}
+	if s.DragLineWidth <= 0 {
+		s.DragLineWidth = defaults.DragLineWidth
+	}
+	if s.DotRadiusRatio <= 0 {
+		s.DotRadiusRatio = defaults.DotRadiusRatio
+	}
+	if s.HoverColor == nil {
+		s.HoverColor = defaults.HoverColor
+	}
+	if s.HitRadiusRatio <= 0 {
+		s.HitRadiusRatio = defaults.HitRadiusRatio
+	}
+	if s.DotImages == nil {
+		s.DotImages = defaults.DotImages
+	}
+	return s
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// The style and status height for the theme, resolved again only when
+// the theme, its variant or the style changed. The caller holds the
+// lock.
+func (p *PatternLock) resolveLocked(th fyne.Theme, variant fyne.ThemeVariant) *resolvedStyle {
+	r := &p.resolved
+	if r.valid && r.variant == variant && sameTheme(r.theme, th) {
+		return r
+	}
+	r.style = p.style.withDefaults(DefaultPatternLockStyle(th, variant))
+	r.statusHeight = statusHeight(th)
+	r.theme, r.variant, r.valid = th, variant, true
+	return r
+}
+
+// the image of the dot, nil if it is drawn as a circle
+func (s PatternLockStyle) dotImage(index int) fyne.Resource {
+	if index < 0 || index >= len(s.DotImages) {
+		return nil
+	}
+	return s.DotImages[index]
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// the variant of the running application, dark without one
+func themeVariant() fyne.ThemeVariant {
+	if fyne.CurrentApp() == nil {
+		return theme.VariantDark
+	}
+	return fyne.CurrentApp().Settings().ThemeVariant()
+}
+
+// Whether both are the same theme instance. Only pointers are compared,
+// a theme of another kind is never the same and is resolved every time.
+func sameTheme(a, b fyne.Theme) bool {
+	kind := reflect.TypeOf(a)
+	return kind != nil && kind.Kind() == reflect.Pointer && kind == reflect.TypeOf(b) && a == b
+}
@@ -0,0 +1,94 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the PatternLock style: the defaults taken from the theme
+ * and its variant, and the zero fields of a style that keep them.
+ ********************************************************************/
+package fynex
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestDefaultPatternLockStyle(t *testing.T) {
+	themes := []struct {
+		name  string
+		theme fyne.Theme
+	}{
+		{"default", theme.DefaultTheme()},
+		{"test", test.NewTheme()},
+	}
+	for _, th := range themes {
+		for _, variant := range []fyne.ThemeVariant{theme.VariantLight, theme.VariantDark} {
+			style := DefaultPatternLockStyle(th.theme, variant)
+			colors := []struct {
+				field string
+				got   color.Color
+				name  fyne.ThemeColorName
+			}{
+				{"IdleDotColor", style.IdleDotColor, theme.ColorNamePlaceHolder},
+				{"SelectedColor", style.SelectedColor, theme.ColorNamePrimary},
+				{"NeutralColor", style.NeutralColor, theme.ColorNameForeground},
+				{"WrongColor", style.WrongColor, theme.ColorNameError},
+				{"GrantedColor", style.GrantedColor, theme.ColorNameSuccess},
+				{"HoverColor", style.HoverColor, theme.ColorNameFocus},
+			}
+			for _, c := range colors {
+				if want := th.theme.Color(c.name, variant); !sameColor(c.got, want) {
+					t.Errorf("%s theme, variant %d: %s = %v, want the %s color %v", th.name, variant, c.field, c.got, c.name, want)
+				}
+			}
+			// the overlay is the background, see through
+			overlay := color.NRGBAModel.Convert(th.theme.Color(theme.ColorNameBackground, variant)).(color.NRGBA)
+			overlay.A = defaultOVERLAY_ALPHA
+			if !sameColor(style.OverlayColor, overlay) {
+				t.Errorf("%s theme, variant %d: OverlayColor = %v, want %v", th.name, variant, style.OverlayColor, overlay)
+			}
+			if style.LineWidth != DefaultLineWidth || style.DragLineWidth != DefaultDragLineWidth ||
+				style.DotRadiusRatio != DefaultDotRadiusRatio || style.HitRadiusRatio != DefaultHitRadiusRatio {
+				t.Errorf("%s theme, variant %d: sizes %+v", th.name, variant, style)
+			}
+		}
+	}
+}
+
+func TestStyleFromTheme(t *testing.T) {
+	app := test.NewTempApp(t)
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	p.SetStyle(PatternLockStyle{WrongColor: red, LineWidth: 9})
+
+	for _, th := range []fyne.Theme{theme.DefaultTheme(), test.NewTheme()} {
+		app.Settings().SetTheme(th)
+		defaults := DefaultPatternLockStyle(th, themeVariant())
+		style := p.Style()
+		// the fields that were set are kept
+		if !sameColor(style.WrongColor, red) || style.LineWidth != 9 {
+			t.Errorf("WrongColor %v and LineWidth %g, want the style's %v and 9", style.WrongColor, style.LineWidth, red)
+		}
+		// the zero fields follow the theme
+		if !sameColor(style.SelectedColor, defaults.SelectedColor) || !sameColor(style.IdleDotColor, defaults.IdleDotColor) {
+			t.Errorf("SelectedColor %v and IdleDotColor %v, want the theme's %v and %v",
+				style.SelectedColor, style.IdleDotColor, defaults.SelectedColor, defaults.IdleDotColor)
+		}
+		if style.DragLineWidth != DefaultDragLineWidth {
+			t.Errorf("DragLineWidth = %g, want %d", style.DragLineWidth, DefaultDragLineWidth)
+		}
+	}
+
+	// the empty style goes back to the default look
+	p.SetStyle(PatternLockStyle{})
+	if style := p.Style(); style.LineWidth != DefaultLineWidth || sameColor(style.WrongColor, red) {
+		t.Errorf("style %+v after SetStyle(PatternLockStyle{})", style)
+	}
+}
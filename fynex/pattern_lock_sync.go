@@ -0,0 +1,182 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Concurrency model and data bindings of the PatternLock widget.
+ *
+ * Fyne calls the input handlers, the animation ticks and the renderer
+ * on its main goroutine. The drawing state (the sequence being drawn,
+ * the pointer, the animations) lives there and the exported Sequence
+ * field must only be touched there too. The configuration (valid
+ * pattern, design state, colors, modes, status) is guarded by p.mux:
+ *
+ *   - the setters can be called from any goroutine. They update the
+ *     configuration under the lock and hand any UI work to fyne.Do.
+ *   - nothing calls Refresh() or a user callback while holding the
+ *     lock, callbacks are free to call back into the widget.
+ *   - the renderer draws from a snapshot copied under the lock.
+ *
+ * Other widgets can observe the status and the live sequence through
+ * the bindings, which are updated on every refresh.
+ ********************************************************************/
+package fynex
+
+import (
+	"image/color"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+)
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// what the renderer needs to draw the widget, copied under the lock
+type patternState struct {
+	background  *fyne.StaticResource
+	status      string
+	style       PatternLockStyle // with the theme defaults
+	lineColor   color.Color      // selected color, or the design color
+	designing   bool
+	meter       bool // show the strength meter
+	effect      pathEffect
+	stealth     StealthMode // as visible right now (playback shows all)
+	sequence    []int
+	active      bool
+	hover       fyne.Position
+	hoverDot    int // the dot with the hover ring, -1 if none
+	pulsing     bool
+	pulseIndex  int
+	pulseScale  float32
+	focused     bool
+	cursor      int
+	minStrength StrengthLevel
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// The status message as a binding, e.g. to show it in a widget.Label
+// bound with widget.NewLabelWithData(). Setting it changes the status.
+func (p *PatternLock) StatusBinding() binding.String {
+	return p.statusData
+}
+
+// The dots drawn so far (internal indices) as a binding. It is updated
+// while drawing and emptied when the drawing is cleared. It is meant to
+// be observed, setting it has no effect on the widget.
+func (p *PatternLock) SequenceBinding() binding.IntList {
+	return p.sequenceData
+}
+
+// implements fyne.Widget. Besides redrawing, it updates the bindings
+// and the external status widget. Call it on the main goroutine.
+func (p *PatternLock) Refresh() {
+	p.mux.Lock()
+	status, target := p.Status, p.statusTarget
+	forward := target != nil && (p.statusRetarget || p.statusForwarded != status)
+	if forward {
+		p.statusForwarded = status
+		p.statusRetarget = false
+	}
+	p.mux.Unlock()
+
+	if p.statusData != nil {
+		if current, err := p.statusData.Get(); err != nil || current != status {
+			p.statusData.Set(status)
+		}
+	}
+	if p.sequenceData != nil && !slices.Equal(p.publishedSequence, p.Sequence) {
+		p.publishedSequence = slices.Clone(p.Sequence)
+		p.sequenceData.Set(slices.Clone(p.Sequence))
+	}
+	if forward {
+		target.SetText(status)
+	}
+
+	p.BaseWidget.Refresh()
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// creates the bindings, a status set through its binding is shown
+func (p *PatternLock) bindData() {
+	p.statusData = binding.NewString()
+	p.sequenceData = binding.NewIntList()
+	p.statusData.AddListener(binding.NewDataListener(func() {
+		status, err := p.statusData.Get()
+		if err != nil {
+			return
+		}
+		p.mux.Lock()
+		changed := status != p.Status
+		p.mux.Unlock()
+		if changed {
+			p.SetStatus(status)
+		}
+	}))
+}
+
+// the renderer's copy of the widget state
+func (p *PatternLock) renderState() patternState {
+	th := p.Theme()
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	style := p.resolveLocked(th, themeVariant()).style
+	state := patternState{
+		background:  p.backgroundRsrc,
+		status:      p.Status,
+		style:       style,
+		lineColor:   style.SelectedColor,
+		designing:   p.designing,
+		meter:       p.designing && p.strengthMeter,
+		effect:      p.effect,
+		stealth:     p.stealth,
+		sequence:    slices.Clone(p.Sequence),
+		active:      p.active,
+		hover:       p.hover,
+		hoverDot:    p.hoverDot,
+		pulsing:     p.pulse != nil,
+		pulseIndex:  p.pulseIndex,
+		pulseScale:  p.pulseScale,
+		focused:     p.focused,
+		cursor:      p.cursor,
+		minStrength: p.minStrength,
+	}
+	if p.designing {
+		state.lineColor = style.DesignColor
+	}
+	if p.effect.color != nil {
+		// validation feedback
+		state.lineColor = p.effect.color
+	}
+	if p.active || p.lockedOut {
+		// the drawing shows where the pointer is
+		state.hoverDot = -1
+	}
+	if p.playing {
+		// playback is never hidden
+		state.stealth = StealthOff
+	}
+	return state
+}
+
+// runs fn on the main goroutine, right away when there is no app
+func (p *PatternLock) onMain(fn func()) {
+	if fyne.CurrentApp() == nil {
+		fn()
+		return
+	}
+	fyne.Do(fn)
+}
+
+// refreshes the widget, it may be called from any goroutine
+func (p *PatternLock) refreshUI() {
+	p.onMain(p.Refresh)
+}
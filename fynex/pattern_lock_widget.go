@@ -3,7 +3,8 @@
  *                      All Rights Reserved
  * -----------------------------------------------------------------
  * A Lock Pattern widget similar to those used to unblock the screens
- * of smartphones. This widget supports 3x3, 4x4 and 5x5 grids.
+ * of smartphones. This widget supports 3x3, 4x4 and 5x5 grids as well
+ * as any rectangular grid of up to 9x9 dots.
  ********************************************************************/
 package fynex
 
@@ -11,11 +12,14 @@ import (
 	"fmt"
 	"image/color"
 	"log"
-	"math"
 	"reflect"
+	"slices"
 	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -27,8 +31,8 @@ const MSG_STATUS_UNBLOCK = "Draw the pattern to unblock"
 const MSG_STATUS_DEFINE = "Draw NEW Pattern"
 const MSG_STATUS_WRONG = "Wrong Pattern. Try again."
 const MSG_STATUS_GRANTED = "Access Granted!"
-
-var defaultColor = color.NRGBA{R: 0, G: 200, B: 255, A: 200} // #C8FFC8
+const MSG_STATUS_LOCKED = "Too many attempts. Try again in %s"
+const MSG_STATUS_WEAK = "Pattern too weak. Try again"
 
 /* -----------------------------------------------------------------
  *                     I N T E R F A C E S
@@ -37,6 +41,9 @@ var defaultColor = color.NRGBA{R: 0, G: 200, B: 255, A: 200} // #C8FFC8
 var _ fyne.Widget = (*PatternLock)(nil)
 var _ fyne.Tappable = (*PatternLock)(nil)
 var _ fyne.Draggable = (*PatternLock)(nil)
+var _ fyne.Focusable = (*PatternLock)(nil)
+var _ fyne.Shortcutable = (*PatternLock)(nil)
+var _ desktop.Hoverable = (*PatternLock)(nil)
 
 /* -----------------------------------------------------------------
  *                          T Y P E S
@@ -44,19 +51,91 @@ var _ fyne.Draggable = (*PatternLock)(nil)
 
 type PatternLock struct {
 	widget.BaseWidget
-	GridSize    int
-	Sequence    []int
+	// the number of columns of the grid. Kept for compatibility, it is
+	// read-only: the grid is set by the constructor.
+	GridSize int
+	Sequence []int
+	// Called with every completed drawing. In design state it is only
+	// called once, with the confirmed new pattern, and not for the first
+	// drawing or a refused one.
 	OnComplete  func([]int)
 	OnValidated func(bool)
 	Status      string
-
-	descriptor     *PatternInfo
-	selectedColor  color.NRGBA
-	backgroundRsrc *fyne.StaticResource
-	active         bool // mouse is being dragged to draw a pattern
-	designing      bool // entered pattern design mode (no validation)
-	hover          fyne.Position
-	mux            sync.Mutex
+	// called when a lockout begins, with its duration
+	OnLockedOut func(time.Duration)
+	// called when a lockout has expired and input is accepted again
+	OnLockReleased func()
+	// called when a new pattern was drawn and confirmed in design state
+	OnDefined func(*PatternInfo)
+	// called when a new pattern is refused or the design is cancelled
+	OnDefineFailed func(error)
+	// called when a drawing session begins (first touch or key)
+	OnStart func()
+	// called for every dot added to the drawing, with the dot center
+	OnDotAdded func(index int, position fyne.Position)
+	// called when the pattern is released without any dot
+	OnCancel func()
+	// called when a drawing in progress is cleared without release
+	OnReset func()
+	// called with the timing trace when a drawing session ends
+	OnTrace func(*PatternTrace)
+	// called before OnValidated when an enrolled identity was drawn
+	OnIdentified func(userID string)
+	// called before OnValidated when the duress pattern was drawn
+	OnDuress func()
+
+	gridMode          PatternMode // columns and rows of the grid
+	descriptor        *PatternInfo
+	hash              *PatternHash
+	style             PatternLockStyle // zero fields follow the theme
+	resolved          resolvedStyle    // style and status height of the theme
+	layout            geometryCache    // the last geometry() computed
+	backgroundRsrc    *fyne.StaticResource
+	active            bool // mouse is being dragged to draw a pattern
+	designing         bool // entered pattern design mode (no validation)
+	designStep        designStep
+	candidate         *PatternInfo // first drawing awaiting confirmation
+	prevDescriptor    *PatternInfo // restored by CancelDesign()
+	prevHash          *PatternHash // restored by CancelDesign()
+	passThrough       bool         // automatically add skipped-over dots
+	strengthMeter     bool         // show the live strength indicator when designing
+	minStrength       StrengthLevel
+	rules             PatternRule // new and typed patterns, nil for the defaults
+	hover             fyne.Position
+	hoverDot          int    // dot under the mouse pointer, -1 if none
+	focused           bool   // has keyboard focus
+	cursor            int    // keyboard cursor (dot index)
+	typed             string // pattern notation typed on the keyboard
+	shortcutsBlocked  bool   // TypedShortcut() drops them, see IdleLocker
+	lockout           LockoutPolicy
+	lockedOut         bool            // too many failures, input is ignored
+	verifying         bool            // a drawn pattern is being verified, input is ignored
+	lockGen           int             // identifies the current lockout countdown
+	animation         *fyne.Animation // playback or validation feedback
+	playing           bool            // Play() is animating a pattern
+	effect            pathEffect      // animation applied to the drawn path
+	stealth           StealthMode     // how much of the path is visible
+	pulse             *fyne.Animation // pulse of the last dot (StealthPulse)
+	pulseIndex        int             // the dot that pulses
+	pulseScale        float32         // size factor of the pulsing dot
+	statusPlacement   StatusPlacement
+	statusTarget      StatusDisplay // shows the status when StatusExternal
+	statusForwarded   string        // the last status sent to statusTarget
+	statusRetarget    bool          // statusTarget changed, send the status
+	statusData        binding.String
+	sequenceData      binding.IntList
+	publishedSequence []int           // the sequence last set in sequenceData
+	messages          Messages        // per-instance message overrides
+	session           *PatternTrace   // the drawing session in progress
+	lastTrace         *PatternTrace   // the last drawing session that ended
+	identities        []patternSecret // enrolled users, see EnrollIdentity()
+	duress            *patternSecret  // the silent alarm pattern
+	secretsVersion    uint64          // bumped when a secret is added or replaced
+	store             PatternStore    // where the valid hash came from
+	storeUser         string          // the user of the valid hash in store
+	storeHash         *PatternHash    // the hash loaded from store
+	identityStore     PatternStore    // where the identities came from
+	mux               sync.Mutex
 }
 
 /* -----------------------------------------------------------------
@@ -67,6 +146,12 @@ type PatternLock struct {
  *                  P R I V A T E    T Y P E S
  * -----------------------------------------------------------------*/
 
+// what the background verification found for the drawn pattern
+type patternVerdict struct {
+	valid  bool
+	secret *patternSecret // the identity or duress pattern, nil for the valid one
+}
+
 /* -----------------------------------------------------------------
  *                  C O N S T R U C T O R S
  * -----------------------------------------------------------------*/
@@ -78,16 +163,25 @@ type PatternLock struct {
 // NOTE: This is best used for letting the user a NEW Pattern to replace an
 // old one
 func NewPatternLock(size int, onComplete func([]int)) *PatternLock {
+	return NewPatternLockFor(PatternModeNone.Convert(size), onComplete)
+}
+
+// (ctor) a Lock Pattern widget for any grid, including rectangular ones
+// like 4x6. Otherwise it is just like NewPatternLock().
+func NewPatternLockFor(mode PatternMode, onComplete func([]int)) *PatternLock {
 	p := &PatternLock{
-		GridSize:       size,
-		selectedColor:  defaultColor,
+		GridSize:       mode.Columns(),
+		gridMode:       mode,
 		OnComplete:     onComplete,
 		OnValidated:    nil,
 		descriptor:     nil,
 		backgroundRsrc: nil,
 		active:         false,
 		designing:      false,
+		effect:         noPathEffect,
+		hoverDot:       -1,
 	}
+	p.bindData()
 	p.ExtendBaseWidget(p)
 	return p
 }
@@ -99,9 +193,20 @@ func NewPatternLock(size int, onComplete func([]int)) *PatternLock {
 // NOTE: This is typically used for unblocking when the pattern is already
 // defined.
 func NewPatternLockWith(patternDesc *PatternInfo, onValidated func(bool)) *PatternLock {
-	pl := NewPatternLock(patternDesc.Size(), nil)
+	pl := NewPatternLockFor(patternDesc.Mode(), nil)
 	pl.descriptor = patternDesc
-	pl.GridSize = patternDesc.Size()
+	pl.OnValidated = onValidated
+	return pl
+}
+
+// (ctor) a Lock Pattern widget that validates against a salted pattern
+// hash instead of a plaintext descriptor. The onValidated callback is
+// called at the end of the drawn pattern with the verification result.
+// NOTE: Use this when the pattern is persisted, the widget never holds
+// the plaintext sequence beyond the current drawing.
+func NewPatternLockWithHash(hash *PatternHash, onValidated func(bool)) *PatternLock {
+	pl := NewPatternLockFor(hash.Mode(), nil)
+	pl.hash = hash
 	pl.OnValidated = onValidated
 	return pl
 }
@@ -113,83 +218,222 @@ func NewPatternLockWith(patternDesc *PatternInfo, onValidated func(bool)) *Patte
 // Set the Pattern Lock widget's background image from an (embedded) resource.
 func (p *PatternLock) SetBackground(bg *fyne.StaticResource) *PatternLock {
 	p.mux.Lock()
-	defer p.mux.Unlock()
-
 	p.backgroundRsrc = bg
-	p.Refresh()
+	p.mux.Unlock()
+
+	p.refreshUI()
 	return p
 }
 
+// Shows the message in the status area. It may be called from any
+// goroutine.
 func (p *PatternLock) SetStatus(msg string) {
 	p.mux.Lock()
-	defer p.mux.Unlock()
+	p.showStatus(msg)
+	p.mux.Unlock()
 
-	p.Status = msg
-	p.Refresh()
+	p.refreshUI()
 }
 
 // Set the line drawing color for selected dots in the pattern. The
-// default is a light-blue shade.
+// default is the primary color of the theme. It is a shortcut for the
+// SelectedColor of the PatternLockStyle.
 func (p *PatternLock) SetSelectedColor(selColor color.NRGBA) *PatternLock {
 	p.mux.Lock()
-	defer p.mux.Unlock()
+	p.style.SelectedColor = selColor
+	p.resolved.valid = false
+	p.mux.Unlock()
 
-	p.selectedColor = selColor
+	p.refreshUI()
 	return p
 }
 
+// Go back to the default selected color of the theme
 func (p *PatternLock) ResetColor() {
+	p.mux.Lock()
+	p.style.SelectedColor = nil
+	p.resolved.valid = false
+	p.mux.Unlock()
+
+	p.refreshUI()
+}
+
+// Overrides the messages of this instance, e.g. to customize the status
+// texts. The messages that are not in the map are translated to the
+// current locale. A nil map removes all overrides.
+func (p *PatternLock) SetMessages(messages Messages) *PatternLock {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
-	p.selectedColor = defaultColor
+	p.messages = make(Messages, len(messages))
+	for id, text := range messages {
+		p.messages[id] = text
+	}
+	return p
 }
 
-// Remove the current pattern descriptor to enter Pattern Definition Mode.
-// The next full pattern will be stored as current.
-func (p *PatternLock) EnterDesignState() *PatternLock {
+// Overrides a single message of this instance.
+func (p *PatternLock) SetMessage(id MessageID, text string) *PatternLock {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
+	if p.messages == nil {
+		p.messages = make(Messages)
+	}
+	p.messages[id] = text
+	return p
+}
+
+// Remove the current pattern descriptor to enter Pattern Definition Mode.
+// The user draws the new pattern and then confirms it by drawing it once
+// more. Only then it is stored as current and OnDefined is called. Use
+// CancelDesign() to abandon and restore the previous pattern.
+func (p *PatternLock) EnterDesignState() *PatternLock {
+	p.mux.Lock()
 	log.Print("Entered design state")
+	if !p.designing {
+		p.prevDescriptor = p.descriptor
+		p.prevHash = p.hash
+	}
 	p.descriptor = nil
-	p.active = false // we WILL begin drawing later
+	p.hash = nil
 	p.designing = true
-	p.Status = MSG_STATUS_DEFINE
-	p.Refresh()
+	p.designStep = designDraw
+	p.candidate = nil
+	p.showStatus(p.messages.Get(MsgStatusDefine))
+	p.mux.Unlock()
+
+	// we WILL begin drawing later
+	p.onMain(p.clearDrawing)
 	return p
 }
 
 // Normally this shouldn't be called because PatternLock will
-// automatically leave design-mode if DragEnd is received.
+// automatically leave design-mode once the new pattern is confirmed.
 func (p *PatternLock) leaveDesignState() *PatternLock {
-	p.active = false
 	p.designing = false
-	p.Status = MSG_STATUS_UNBLOCK
+	p.designStep = designDraw
+	p.candidate = nil
+	p.prevDescriptor = nil
+	p.prevHash = nil
+	p.showStatus(p.messages.Get(MsgStatusUnblock))
 	log.Print("Leaving design state")
 	return p
 }
 
-// Sets the valid pattern without changing OnValidated
+// Sets the valid pattern without changing OnValidated. It is refused,
+// and the valid pattern left as it was, if it is the duress pattern or
+// belongs to an identity. The valid pattern is matched first, so they
+// would never be recognized.
 func (p *PatternLock) SetValidPattern(pinfo *PatternInfo) *PatternLock {
-	p.mux.Lock()
-	defer p.mux.Unlock()
+	if err := p.setValid(patternSecret{descriptor: pinfo}); err != nil {
+		log.Print("SetValidPattern refused: ", err)
+		return p
+	}
+	log.Printf("SetValidPattern: %d dots", len(pinfo.Pattern()))
+	return p
+}
 
-	p.descriptor = pinfo
-	log.Print("SetValidPattern:", pinfo.String())
+// Sets the salted hash to validate against without changing OnValidated.
+// It replaces any plaintext pattern descriptor. Like SetValidPattern()
+// it is refused for the duress pattern or the pattern of an identity.
+func (p *PatternLock) SetValidHash(hash *PatternHash) *PatternLock {
+	if err := p.setValid(patternSecret{hash: hash}); err != nil {
+		log.Print("SetValidHash refused: ", err)
+	}
 	return p
 }
 
 // Sets the valid pattern and specify a callback for validation result.
+// The pattern is refused like in SetValidPattern(), the callback is set
+// anyway.
 func (p *PatternLock) SetValidPatternWith(pinfo *PatternInfo, onValidated func(bool)) *PatternLock {
+	p.mux.Lock()
+	p.OnValidated = onValidated
+	p.mux.Unlock()
+
+	return p.SetValidPattern(pinfo)
+}
+
+// Sets the policy that throttles failed attempts. If the policy is
+// already locked (e.g. restored from preferences) the lockout starts
+// immediately. A nil policy allows unlimited retries.
+func (p *PatternLock) SetLockoutPolicy(policy LockoutPolicy) *PatternLock {
+	p.mux.Lock()
+	p.lockout = policy
+	p.mux.Unlock()
+
+	if policy != nil {
+		if until := policy.LockedUntil(); time.Now().Before(until) {
+			p.startLockout(until)
+		}
+	}
+	return p
+}
+
+// whether the widget currently ignores input due to a lockout
+func (p *PatternLock) IsLockedOut() bool {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
-	p.descriptor = pinfo
-	p.OnValidated = onValidated
+	return p.lockedOut
+}
+
+// Whether a drawn pattern is being verified. Verifying a hash takes a
+// while, it runs in the background and input is ignored meanwhile.
+func (p *PatternLock) IsVerifying() bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.verifying
+}
+
+// Enables Android-style pass-through mode: when the pattern goes in a
+// straight line over a dot that was not visited yet (e.g. A1 to C1 over
+// B1) that dot is added automatically. It works for any grid size.
+func (p *PatternLock) SetPassThrough(enable bool) *PatternLock {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.passThrough = enable
+	return p
+}
+
+// Shows a live strength indicator while a new pattern is being drawn in
+// design state. Patterns scoring below the minimum level are refused,
+// use StrengthWeak to accept any pattern.
+func (p *PatternLock) SetStrengthMeter(show bool, minimum StrengthLevel) *PatternLock {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.strengthMeter = show
+	p.minStrength = minimum
+	return p
+}
+
+// Sets the rules that a new pattern must satisfy in design state, and
+// that a typed pattern must satisfy. Nil goes back to
+// DefaultPatternRules. The widget keeps a copy of a PatternRules list.
+func (p *PatternLock) SetPatternRules(rules PatternRule) *PatternLock {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.rules = cloneRules(rules)
 	return p
 }
 
+// the grid mode (columns and rows) of this widget
+func (p *PatternLock) Mode() PatternMode {
+	return p.gridMode
+}
+
+// The center of the dot (internal index) in widget coordinates. It is
+// the same geometry used to hit-test the pointer, so synthetic events
+// sent there always land on the dot.
+func (p *PatternLock) DotCenter(index int) fyne.Position {
+	return p.geometry(p.Size()).dotCenter(index)
+}
+
 func (p *PatternLock) CreateRenderer() fyne.WidgetRenderer {
 	return newPatternRenderer(p)
 }
@@ -198,21 +442,30 @@ func (p *PatternLock) CreateRenderer() fyne.WidgetRenderer {
 // Let's catch user interaction when the mouse down hits the custom widget,
 // not just when the user starts to drag the finger/stylus over the widget.
 func (p *PatternLock) Tapped(e *fyne.PointEvent) {
-	p.active = true
-	if newDot := p.checkHit(e.Position); newDot {
-		relativeX := e.Position.X
-		relativeY := e.Position.Y
-		log.Printf("onTapped (%f,%f)", relativeX, relativeY)
+	if p.inputBlocked() {
+		return
+	}
+	p.requestFocus()
+	if !p.isDrawing() {
+		p.stopAnimation()
 	}
+	p.beginSession()
+	p.active = true
+	p.checkHit(e.Position)
 	p.Refresh()
 }
 
 // Implements fyne.Draggable
 func (p *PatternLock) Dragged(e *fyne.DragEvent) {
+	if p.inputBlocked() {
+		return
+	}
 	// clear status label if it is beginning
-	if !p.active {
+	if !p.isDrawing() {
+		p.stopAnimation()
 		p.SetStatus("")
 	}
+	p.beginSession()
 	p.active = true
 	p.hover = e.Position
 	p.checkHit(e.Position)
@@ -222,32 +475,56 @@ func (p *PatternLock) Dragged(e *fyne.DragEvent) {
 // Implements fyne.Draggable
 func (p *PatternLock) DragEnd() {
 	log.Print("DragEnd")
-	if len(p.Sequence) > 0 {
-		// 1st call OnComplete if defined
-		if p.OnComplete != nil {
-			p.OnComplete(p.Sequence)
-		}
-		// 2nd call OnValidated if defined after updating label.
-		// But only if pattern lock descriptor is defined, else
-		// there is nothing to validate, just accept as new pattern.
-		if !p.designing {
-			if p.descriptor != nil {
-				// validate pattern against current
+	if p.IsVerifying() {
+		// the drag was ignored, the verified path stays
+		return
+	}
+	if p.IsLockedOut() {
+		p.active = false
+		p.Sequence = []int{}
+		p.endSession(TraceReset)
+		p.Refresh()
+		return
+	}
+	p.mux.Lock()
+	designing := p.designing
+	validating := p.descriptor != nil || p.hash != nil || len(p.identities) != 0 || p.duress != nil
+	onComplete := p.OnComplete
+	p.mux.Unlock()
+
+	keepPath := false
+	if len(p.Sequence) == 0 {
+		p.endSession(TraceCancelled)
+	} else {
+		p.endSession(TraceCompleted)
+		if designing {
+			// draw, confirm and accept a new pattern
+			p.onDesigning()
+		} else {
+			// 1st call OnComplete if defined
+			if onComplete != nil {
+				onComplete(slices.Clone(p.Sequence))
+			}
+			// 2nd call OnValidated if defined after updating label.
+			// But only if pattern lock descriptor is defined, else
+			// there is nothing to validate.
+			if validating {
+				// validate pattern against current, the result animation
+				// clears the path when done
 				p.onValidating()
+				keepPath = true
 			}
-		} else {
-			// set new pattern
-			mode := PatternModeNone.Convert(p.GridSize)
-			p.descriptor, _ = NewPattern(p.Sequence, mode)
 		}
 	}
 
-	if p.designing {
-		p.leaveDesignState()
-	} else {
-		p.active = false
+	p.active = false
+	if !keepPath {
+		p.Sequence = []int{}
 	}
-	p.Sequence = []int{}
+	// a touch has no pointer left, a mouse shows it again when it moves
+	p.mux.Lock()
+	p.hoverDot = -1
+	p.mux.Unlock()
 	p.Refresh()
 }
 
@@ -255,69 +532,242 @@ func (p *PatternLock) DragEnd() {
  *                  P R I V A T E    M E T H O D S
  * -----------------------------------------------------------------*/
 
+// whether input is ignored, during a lockout or a verification
+func (p *PatternLock) inputBlocked() bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.lockedOut || p.verifying
+}
+
+// sets the status message, the caller holds the lock and refreshes.
+// The refresh sends it to the bindings and the external status widget.
+func (p *PatternLock) showStatus(msg string) {
+	p.Status = msg
+}
+
+// the message of this instance, the caller must not hold the lock
+func (p *PatternLock) message(id MessageID) string {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.messages.Get(id)
+}
+
+// abandons the drawing in progress (main goroutine)
+func (p *PatternLock) clearDrawing() {
+	p.stopAnimation()
+	p.active = false
+	p.Sequence = []int{}
+	p.endSession(TraceReset)
+	p.Refresh()
+}
+
 // checkHit determines if a position is inside a dot's radius
 func (p *PatternLock) checkHit(pos fyne.Position) bool {
 	added := false
-	size := p.Size()
-	// The renderer adds a label at the top, but p.Size() is the whole widget.
-	// We must account for the label height (approx 30-40px) or ensure coordinates align.
-	colWidth := size.Width / float32(p.GridSize)
-	rowHeight := (size.Height - statusLABEL_OFFSET) / float32(p.GridSize) // 40 is approx label height + padding
-	radius := float64(fyne.Min(colWidth, rowHeight) / 3)                  // Increased tolerance
-
-	col := int(pos.X / colWidth)
-	row := int((pos.Y - statusLABEL_OFFSET) / rowHeight)
-
-	if col >= 0 && col < p.GridSize && row >= 0 && row < p.GridSize {
-		id := row*p.GridSize + col
-		centerX := colWidth*float32(col) + colWidth/2
-		centerY := rowHeight*float32(row) + rowHeight/2 + statusLABEL_OFFSET
-		dist := math.Sqrt(math.Pow(float64(pos.X-centerX), 2) + math.Pow(float64(pos.Y-centerY), 2))
-
-		if dist < radius {
-			for _, v := range p.Sequence {
-				if v == id {
-					return false
-				}
+	if id := p.dotAt(pos); id >= 0 {
+		added = p.addDot(id)
+	}
+
+	return added
+}
+
+// appends a dot to the drawn sequence unless it was already visited. In
+// pass-through mode, or when the rules forbid jumps, any unvisited dot
+// skipped over is added before it.
+func (p *PatternLock) addDot(id int) bool {
+	if p.isVisited(id) {
+		return false
+	}
+	p.mux.Lock()
+	passThrough, stealth := p.passThrough || forbidsJumps(p.rules), p.stealth
+	p.mux.Unlock()
+
+	if passThrough && len(p.Sequence) > 0 {
+		for _, mid := range intermediateDots(p.Sequence[len(p.Sequence)-1], id, p.gridMode.Columns()) {
+			if !p.isVisited(mid) {
+				p.Sequence = append(p.Sequence, mid)
+				p.traceDot(mid)
 			}
-			p.Sequence = append(p.Sequence, id)
-			added = true
-			log.Printf("Added dot: %s", p.getPos(id))
 		}
 	}
+	p.Sequence = append(p.Sequence, id)
+	p.traceDot(id)
+	if stealth == StealthPulse {
+		p.pulseDot(id)
+	}
+	return true
+}
 
-	return added
+// whether the dot is already part of the drawn sequence
+func (p *PatternLock) isVisited(id int) bool {
+	for _, v := range p.Sequence {
+		if v == id {
+			return true
+		}
+	}
+	return false
 }
 
-// Validates the drawn pattern against the pattern that was set
-// in the descriptor, updates the status label and if the OnValidated
-// callback is set, it is called.
+// Validates the drawn pattern against the pattern hash or the pattern
+// that was set in the descriptor, then against the enrolled identities
+// and the duress pattern. A hash is slow to verify on purpose, so all
+// of them are verified in the background and the verdict is shown on
+// the main goroutine, see showVerdict(). The path stays until then.
 func (p *PatternLock) onValidating() {
-	var isValid bool
-	if reflect.DeepEqual(p.Sequence, p.descriptor.Pattern()) {
-		isValid = true
-		p.SetStatus(MSG_STATUS_GRANTED)
+	p.mux.Lock()
+	hash, descriptor := p.hash, p.descriptor
+	identities, duress := slices.Clone(p.identities), p.duress
+	p.verifying = true
+	p.mux.Unlock()
+
+	sequence := slices.Clone(p.Sequence)
+	verify := func() patternVerdict {
+		switch {
+		case hash != nil && VerifyPattern(hash, sequence):
+			return patternVerdict{valid: true}
+		case hash == nil && descriptor != nil && reflect.DeepEqual(sequence, descriptor.Pattern()):
+			return patternVerdict{valid: true}
+		}
+		// then the identities and the duress pattern, hashes as well
+		if matched := matchSecret(identities, duress, sequence); matched != nil {
+			return patternVerdict{valid: true, secret: matched}
+		}
+		return patternVerdict{}
+	}
+	if fyne.CurrentApp() == nil {
+		// no event loop to keep responsive
+		p.showVerdict(verify())
+		return
+	}
+	go func() {
+		verdict := verify()
+		fyne.Do(func() {
+			p.showVerdict(verdict)
+		})
+	}()
+}
+
+// Shows the verdict of the drawn pattern (main goroutine). It updates
+// the status label and if the OnValidated callback is set, it is called.
+func (p *PatternLock) showVerdict(verdict patternVerdict) {
+	p.mux.Lock()
+	onValidated, lockout := p.OnValidated, p.lockout
+	onIdentified, onDuress := p.OnIdentified, p.OnDuress
+	p.mux.Unlock()
+	defer func() {
+		p.mux.Lock()
+		p.verifying = false
+		p.mux.Unlock()
+	}()
+
+	isValid, matched := verdict.valid, verdict.secret
+	switch {
+	case isValid && matched == nil:
+		p.markStoreUsed("")
+	case isValid && matched.userID == "":
+		// looks granted, but the alarm goes off
+		log.Print("Duress pattern drawn")
+		if onDuress != nil {
+			onDuress()
+		}
+	case isValid:
+		log.Print("Identified user ", matched.userID)
+		p.markStoreUsed(matched.userID)
+		if onIdentified != nil {
+			onIdentified(matched.userID)
+		}
+	}
+
+	if isValid {
+		p.SetStatus(p.message(MsgStatusGranted))
 	} else {
-		isValid = false
-		p.SetStatus(MSG_STATUS_WRONG)
+		p.SetStatus(p.message(MsgStatusWrong))
 	}
 
+	p.animateResult(isValid)
+
 	// do the user callback if defined
-	if p.OnValidated != nil {
-		p.OnValidated(isValid)
+	if onValidated != nil {
+		onValidated(isValid)
+	}
+
+	// throttle failed attempts
+	if lockout != nil {
+		if isValid {
+			lockout.RecordSuccess()
+		} else if cooldown := lockout.RecordFailure(time.Now()); cooldown > 0 {
+			p.startLockout(lockout.LockedUntil())
+		}
+	}
+}
+
+// blocks input until the given time while the status label shows a
+// countdown. The OnLockedOut and OnLockReleased callbacks bracket it.
+func (p *PatternLock) startLockout(until time.Time) {
+	p.mux.Lock()
+	p.lockedOut = true
+	p.lockGen++
+	generation := p.lockGen
+	p.mux.Unlock()
+
+	remaining := lockoutRemaining(until, time.Now())
+	log.Printf("Locked out for %s", remaining)
+	p.SetStatus(fmt.Sprintf(p.message(MsgStatusLocked), remaining))
+	if p.OnLockedOut != nil {
+		p.OnLockedOut(remaining)
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			remaining := lockoutRemaining(until, now)
+			done := remaining == 0
+			fyne.Do(func() {
+				p.mux.Lock()
+				current := p.lockGen == generation
+				p.mux.Unlock()
+				if !current {
+					return
+				}
+				if done {
+					p.releaseLockout()
+				} else {
+					p.SetStatus(fmt.Sprintf(p.message(MsgStatusLocked), remaining))
+				}
+			})
+			if done {
+				return
+			}
+		}
+	}()
+}
+
+// accept input again after a lockout expired
+func (p *PatternLock) releaseLockout() {
+	p.mux.Lock()
+	p.lockedOut = false
+	p.mux.Unlock()
+
+	log.Print("Lockout released")
+	p.SetStatus(p.message(MsgStatusUnblock))
+	if p.OnLockReleased != nil {
+		p.OnLockReleased()
 	}
 }
 
-// get the column as in A,B,C,(D)
+// get the column as in A,B,C,(D...I)
 func (p *PatternLock) getColumn(index int) rune {
-	column := index % p.GridSize
+	column := index % p.gridMode.Columns()
 	columnChar := rune(65 + column)
 	return columnChar
 }
 
-// get the row as 1,2,3,(4)
+// get the row as 1,2,3,(4...9)
 func (p *PatternLock) getRow(index int) int {
-	row := int(index/p.GridSize) + 1
+	row := int(index/p.gridMode.Columns()) + 1
 	return row
 }
 
@@ -0,0 +1,226 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Composable validation rules for unlock patterns: length limits,
+ * revisits, jumps over unvisited dots, common shapes and direction
+ * changes. NewPattern(), the notation parser and the design mode of
+ * the PatternLock widget all check the same rules.
+ ********************************************************************/
+package fynex
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+// The rules used when none are given: at least as many dots as the
+// shortest side of the grid, as NewPattern() always asked. Every other
+// rule, like NoRevisits() or NoJumps(), must be added. Unlike Android,
+// NewPattern() and the parser accept jumps by default: a widget without
+// pass-through mode records only the dots hit, so a straight drag over
+// a dot gives a jump, and patterns saved that way must keep working.
+var DefaultPatternRules = PatternRules{MinLength(0)}
+
+/* -----------------------------------------------------------------
+ *                     I N T E R F A C E S
+ * -----------------------------------------------------------------*/
+
+// A PatternRule accepts or refuses a sequence of dots (internal
+// indices) for a grid. The sequence is known to be on the grid and
+// without adjacent duplicates. A refusal should be a *PatternRuleError
+// so that the offending dot can be pointed at.
+type PatternRule interface {
+	Check(sequence []int, mode PatternMode) error
+}
+
+var _ PatternRule = PatternRules(nil)
+var _ PatternRule = PatternRuleFunc(nil)
+var _ PatternRule = noJumpsRule{}
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// Several rules checked in order, the first refusal wins
+type PatternRules []PatternRule
+
+// A function used as a PatternRule
+type PatternRuleFunc func(sequence []int, mode PatternMode) error
+
+// A pattern refused by a rule
+type PatternRuleError struct {
+	Index  int // the offending dot in the sequence, -1 for the whole pattern
+	Reason string
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// the rule of NoJumps(), the widget recognizes it to add skipped dots
+type noJumpsRule struct{}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// implements PatternRule
+func (rules PatternRules) Check(sequence []int, mode PatternMode) error {
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		if err := rule.Check(sequence, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// implements PatternRule
+func (f PatternRuleFunc) Check(sequence []int, mode PatternMode) error {
+	return f(sequence, mode)
+}
+
+// implements PatternRule
+func (noJumpsRule) Check(sequence []int, mode PatternMode) error {
+	if at, mid := findJump(sequence, mode); at >= 0 {
+		return &PatternRuleError{Index: at,
+			Reason: "jumps over unvisited dot " + PatternInfoString(mode, []int{mid})}
+	}
+	return nil
+}
+
+// implements the error interface
+func (e *PatternRuleError) Error() string {
+	if e.Index < 0 {
+		return "pattern " + e.Reason
+	}
+	return fmt.Sprintf("pattern %s at [%d]", e.Reason, e.Index)
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// At least n dots. Zero or less means the shortest side of the grid.
+func MinLength(n int) PatternRule {
+	return PatternRuleFunc(func(sequence []int, mode PatternMode) error {
+		minimum := n
+		if minimum <= 0 {
+			minimum = mode.minSide()
+		}
+		if len(sequence) < minimum {
+			return &PatternRuleError{Index: -1,
+				Reason: fmt.Sprintf("needs at least %d dots for %s", minimum, mode)}
+		}
+		return nil
+	})
+}
+
+// At most n dots
+func MaxLength(n int) PatternRule {
+	return PatternRuleFunc(func(sequence []int, mode PatternMode) error {
+		if len(sequence) > n {
+			return &PatternRuleError{Index: n, Reason: fmt.Sprintf("has more than %d dots", n)}
+		}
+		return nil
+	})
+}
+
+// Every dot is visited once at most. The widget never produces a
+// revisit when drawing, add this rule to refuse them in typed patterns.
+func NoRevisits() PatternRule {
+	return PatternRuleFunc(func(sequence []int, mode PatternMode) error {
+		for i, index := range sequence {
+			if slices.Contains(sequence[:i], index) {
+				return &PatternRuleError{Index: i,
+					Reason: "revisits dot " + PatternInfoString(mode, []int{index})}
+			}
+		}
+		return nil
+	})
+}
+
+// The pattern never jumps over a dot that was not visited yet. Like on
+// Android, passing over an already visited dot is allowed. A widget
+// with this rule adds the skipped dots while drawing, as in
+// pass-through mode, so that a drag is never refused for a jump. It is
+// opt-in, see DefaultPatternRules for why.
+func NoJumps() PatternRule {
+	return noJumpsRule{}
+}
+
+// Refuses the common shapes that an attacker would try first, see
+// AnalyzePattern(). Without shapes every known shape is refused.
+func DenyShapes(shapes ...PatternShape) PatternRule {
+	if len(shapes) == 0 {
+		shapes = []PatternShape{ShapeLine, ShapeL, ShapeZ, ShapeN, ShapeU, ShapeSquare}
+	}
+	return PatternRuleFunc(func(sequence []int, mode PatternMode) error {
+		strength := AnalyzePattern(sequence, mode)
+		denied := make([]string, 0)
+		for _, shape := range shapes {
+			if strength.HasShape(shape) {
+				denied = append(denied, string(shape))
+			}
+		}
+		if len(denied) != 0 {
+			return &PatternRuleError{Index: -1,
+				Reason: "has a common shape: " + strings.Join(denied, ", ")}
+		}
+		return nil
+	})
+}
+
+// The drawing changes direction at least n times
+func MinDirectionChanges(n int) PatternRule {
+	return PatternRuleFunc(func(sequence []int, mode PatternMode) error {
+		if changes := AnalyzePattern(sequence, mode).DirectionChanges; changes < n {
+			return &PatternRuleError{Index: -1,
+				Reason: fmt.Sprintf("changes direction %d times, needs %d", changes, n)}
+		}
+		return nil
+	})
+}
+
+// whether the rules contain NoJumps(), nil rules are the defaults
+func forbidsJumps(rules PatternRule) bool {
+	switch r := rulesOrDefault(rules).(type) {
+	case noJumpsRule:
+		return true
+	case PatternRules:
+		return slices.ContainsFunc(r, func(rule PatternRule) bool {
+			return rule != nil && forbidsJumps(rule)
+		})
+	}
+	return false
+}
+
+// A copy of the rules that the caller can no longer change, nested
+// PatternRules are copied too.
+func cloneRules(rules PatternRule) PatternRule {
+	list, ok := rules.(PatternRules)
+	if !ok {
+		return rules
+	}
+	copied := append(PatternRules(nil), list...)
+	for i, rule := range copied {
+		copied[i] = cloneRules(rule)
+	}
+	return copied
+}
+
+// the rules to check, the defaults when none are given
+func rulesOrDefault(rules PatternRule) PatternRule {
+	if rules == nil {
+		return DefaultPatternRules
+	}
+	return rules
+}
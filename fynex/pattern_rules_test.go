@@ -0,0 +1,121 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the pattern validation rules: the defaults that match the
+ * original checks of NewPattern(), each rule on its own, composition
+ * and the copies kept of the caller's rules.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"testing"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestDefaultPatternRules(t *testing.T) {
+	// only what NewPattern() checked before there were rules
+	tests := []struct {
+		name     string
+		sequence []int
+		mode     PatternMode
+		wantErr  bool
+	}{
+		{"three dots on 3x3", []int{0, 1, 2}, PatternMode3x3, false},
+		{"two dots on 3x3", []int{0, 1}, PatternMode3x3, true},
+		{"three dots on 4x4", []int{0, 1, 2}, PatternMode4x4, true},
+		{"revisit", []int{0, 1, 4, 1, 2}, PatternMode3x3, false},
+		{"jump", []int{0, 2, 8}, PatternMode3x3, false},
+		{"adjacent duplicate", []int{0, 1, 1, 2}, PatternMode3x3, true},
+		{"off the grid", []int{0, 1, 9}, PatternMode3x3, true},
+		{"no mode", []int{0, 1, 2}, PatternModeNone, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPattern(tt.sequence, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewPattern(%v, %s) error = %v, want error %t", tt.sequence, tt.mode, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPatternRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      PatternRule
+		sequence  []int
+		wantIndex int // of the refused dot, -2 when accepted
+	}{
+		{"min length", MinLength(5), []int{0, 1, 2, 5, 8}, -2},
+		{"min length short", MinLength(5), []int{0, 1, 2, 5}, -1},
+		{"max length", MaxLength(4), []int{0, 1, 2, 5}, -2},
+		{"max length long", MaxLength(4), []int{0, 1, 2, 5, 8}, 4},
+		{"no revisits", NoRevisits(), []int{0, 1, 2, 5, 8}, -2},
+		{"no revisits revisit", NoRevisits(), []int{0, 1, 4, 1, 2}, 3},
+		{"no jumps", NoJumps(), []int{0, 1, 2, 5, 8}, -2},
+		{"no jumps over a visited dot", NoJumps(), []int{1, 0, 2, 5}, -2},
+		{"no jumps jump", NoJumps(), []int{0, 2, 5, 8}, 1},
+		{"knight move is no jump", NoJumps(), []int{0, 5, 6}, -2},
+		{"direction changes", MinDirectionChanges(1), []int{0, 1, 2, 5, 8}, -2},
+		{"direction changes few", MinDirectionChanges(2), []int{0, 1, 2, 5, 8}, -1},
+		{"composed", PatternRules{MinLength(3), nil, NoRevisits()}, []int{0, 1, 2}, -2},
+		{"composed first refusal", PatternRules{MaxLength(3), NoRevisits()}, []int{0, 1, 0, 1}, 3},
+		{"nested", PatternRules{PatternRules{NoJumps()}}, []int{0, 2, 5}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Check(tt.sequence, PatternMode3x3)
+			if tt.wantIndex == -2 {
+				if err != nil {
+					t.Errorf("Check(%v) error = %v", tt.sequence, err)
+				}
+				return
+			}
+			var ruleErr *PatternRuleError
+			if !errors.As(err, &ruleErr) {
+				t.Fatalf("Check(%v) error = %v, want a *PatternRuleError", tt.sequence, err)
+			}
+			if ruleErr.Index != tt.wantIndex {
+				t.Errorf("Check(%v) refused dot %d, want %d", tt.sequence, ruleErr.Index, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestForbidsJumps(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules PatternRule
+		want  bool
+	}{
+		{"defaults", nil, false},
+		{"no jumps", NoJumps(), true},
+		{"in a list", PatternRules{MinLength(4), NoJumps()}, true},
+		{"nested", PatternRules{PatternRules{nil, NoJumps()}}, true},
+		{"others", PatternRules{MinLength(4), NoRevisits()}, false},
+		{"function", PatternRuleFunc(func([]int, PatternMode) error { return nil }), false},
+	}
+	for _, tt := range tests {
+		if got := forbidsJumps(tt.rules); got != tt.want {
+			t.Errorf("%s: forbidsJumps() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRulesAreCopied(t *testing.T) {
+	rules := PatternRules{NoRevisits()}
+	p := NewPatternLockFor(PatternMode3x3, nil).SetPatternRules(rules)
+	rules[0] = MaxLength(1)
+
+	p.mux.Lock()
+	kept := p.rules
+	p.mux.Unlock()
+	if err := kept.Check([]int{0, 1, 2, 5}, PatternMode3x3); err != nil {
+		t.Errorf("changing the caller's slice changed the widget rules: %v", err)
+	}
+}
@@ -0,0 +1,306 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * The space of patterns of a grid: random patterns for demo accounts
+ * and tests, and the count or the list of every pattern that a set
+ * of rules accepts, the security margin of a pattern mode.
+ ********************************************************************/
+package fynex
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+// the random patterns tried before GeneratePattern gives up
+const generateATTEMPTS = 1000
+
+// no pattern that satisfies the rules was found
+var ErrNoPattern = errors.New("no pattern satisfies the rules")
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// The moves that can be drawn on a grid: every dot once and no jump
+// over a dot that was not visited yet.
+type patternSpace struct {
+	mode      PatternMode
+	minLength int
+	maxLength int
+	between   [][][]int // the dots between two dots
+}
+
+// the visited dots of a pattern, enough for a 9x9 grid
+type dotSet [2]uint64
+
+// a pattern being counted: its visited dots and the last one
+type countKey struct {
+	visited dotSet
+	last    int
+}
+
+/* -----------------------------------------------------------------
+ *                  C O N S T R U C T O R S
+ * -----------------------------------------------------------------*/
+
+// (ctor) the patterns of the grid from minLength to maxLength dots.
+// Zero or less means the shortest side of the grid and all the dots.
+func newPatternSpace(mode PatternMode, minLength, maxLength int) (*patternSpace, error) {
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("invalid pattern mode %s", mode)
+	}
+	if minLength <= 0 {
+		minLength = mode.minSide()
+	}
+	if maxLength <= 0 {
+		maxLength = mode.Dots()
+	}
+	if minLength > maxLength || maxLength > mode.Dots() {
+		return nil, fmt.Errorf("invalid pattern length %d..%d for %s", minLength, maxLength, mode)
+	}
+
+	dots := mode.Dots()
+	between := make([][][]int, dots)
+	for from := range between {
+		between[from] = make([][]int, dots)
+		for to := range between[from] {
+			between[from][to] = intermediateDots(from, to, mode.Width())
+		}
+	}
+	return &patternSpace{mode: mode, minLength: minLength, maxLength: maxLength, between: between}, nil
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// Draws a random pattern of minLength to maxLength dots (see
+// CountPatterns) that the rules accept. Every length is equally likely,
+// and so is every move from a dot. A nil source is seeded from
+// crypto/rand, give a seeded one to get the same patterns every time.
+// It fails with ErrNoPattern when none of the patterns tried is
+// accepted.
+func GeneratePattern(mode PatternMode, minLength, maxLength int, rules PatternRule, src rand.Source) (*PatternInfo, error) {
+	space, err := newPatternSpace(mode, minLength, maxLength)
+	if err != nil {
+		return nil, err
+	}
+	rules = space.narrow(rules)
+	if space.minLength > space.maxLength {
+		return nil, ErrNoPattern
+	}
+	if src == nil {
+		var seed [32]byte
+		if _, err := crand.Read(seed[:]); err != nil {
+			return nil, err
+		}
+		src = rand.NewChaCha8(seed)
+	}
+	rng := rand.New(src)
+
+	for range generateATTEMPTS {
+		length := space.minLength + rng.IntN(space.maxLength-space.minLength+1)
+		sequence := space.randomWalk(rng, length)
+		if rules == nil || rules.Check(sequence, mode) == nil {
+			return NewPatternWithRules(sequence, mode, PatternRules{})
+		}
+	}
+	return nil, ErrNoPattern
+}
+
+// The number of patterns of minLength to maxLength dots that can be
+// drawn on the grid and that the rules accept. Zero or less means the
+// shortest side of the grid and all the dots. Drawable means like on
+// Android: every dot once at most and no jump over an unvisited dot,
+// so CountPatterns(PatternMode3x3, 4, 9, nil) is 389,112.
+// As everywhere nil means DefaultPatternRules, so patterns shorter
+// than the shortest side of the grid are not counted. An empty
+// PatternRules accepts them all: counting the 9 patterns of one dot
+// needs CountPatterns(PatternMode3x3, 1, 1, PatternRules{}).
+// With nil or empty rules the patterns are counted without being
+// listed, which is fast up to 4x4. Otherwise every pattern is checked,
+// see EnumeratePatterns(). Grids over 16 dots are only practical with
+// a short maxLength.
+func CountPatterns(mode PatternMode, minLength, maxLength int, rules PatternRule) (uint64, error) {
+	space, err := newPatternSpace(mode, minLength, maxLength)
+	if err != nil {
+		return 0, err
+	}
+
+	rules = space.narrow(rules)
+	if rules == nil {
+		memo := make(map[countKey]uint64)
+		var total uint64
+		for start := 0; start < mode.Dots(); start++ {
+			total += space.countFrom(dotSet{}.with(start), start, memo)
+		}
+		return total, nil
+	}
+
+	var total uint64
+	space.walk(func(sequence []int) bool {
+		if rules.Check(sequence, mode) == nil {
+			total++
+		}
+		return true
+	})
+	return total, nil
+}
+
+// Calls fn with every pattern counted by CountPatterns, shorter ones
+// first from the same start. The sequence is reused, clone it to keep
+// it. Returning false stops the enumeration.
+func EnumeratePatterns(mode PatternMode, minLength, maxLength int, rules PatternRule, fn func(sequence []int) bool) error {
+	space, err := newPatternSpace(mode, minLength, maxLength)
+	if err != nil {
+		return err
+	}
+
+	rules = space.narrow(rules)
+	space.walk(func(sequence []int) bool {
+		if rules != nil && rules.Check(sequence, mode) != nil {
+			return true
+		}
+		return fn(sequence)
+	})
+	return nil
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// whether the dot is in the set
+func (ds dotSet) has(dot int) bool {
+	return ds[dot/64]&(1<<(dot%64)) != 0
+}
+
+// the set with the dot added
+func (ds dotSet) with(dot int) dotSet {
+	ds[dot/64] |= 1 << (dot % 64)
+	return ds
+}
+
+// the number of dots in the set
+func (ds dotSet) count() int {
+	return bits.OnesCount64(ds[0]) + bits.OnesCount64(ds[1])
+}
+
+// whether the dot can be drawn next after from
+func (s *patternSpace) canMove(visited dotSet, from, to int) bool {
+	if visited.has(to) {
+		return false
+	}
+	for _, mid := range s.between[from][to] {
+		if !visited.has(mid) {
+			return false
+		}
+	}
+	return true
+}
+
+// Narrows the space to the rules and returns those that are left to
+// check, nil when the space has only patterns they accept. Nil rules
+// are the DefaultPatternRules, which only ask for the shortest side of
+// the grid, and an empty PatternRules accepts any pattern.
+func (s *patternSpace) narrow(rules PatternRule) PatternRule {
+	if rules == nil {
+		s.minLength = max(s.minLength, s.mode.minSide())
+		return nil
+	}
+	if list, ok := rules.(PatternRules); ok && len(list) == 0 {
+		return nil
+	}
+	return rules
+}
+
+// A random pattern of the given length. Some unvisited dot can always
+// be reached, the first one on the way to any other, so it never gets
+// stuck.
+func (s *patternSpace) randomWalk(rng *rand.Rand, length int) []int {
+	dots := s.mode.Dots()
+	sequence := []int{rng.IntN(dots)}
+	visited := dotSet{}.with(sequence[0])
+	moves := make([]int, 0, dots)
+	for len(sequence) < length {
+		from := sequence[len(sequence)-1]
+		moves = moves[:0]
+		for to := 0; to < dots; to++ {
+			if s.canMove(visited, from, to) {
+				moves = append(moves, to)
+			}
+		}
+		next := moves[rng.IntN(len(moves))]
+		sequence = append(sequence, next)
+		visited = visited.with(next)
+	}
+	return sequence
+}
+
+// calls fn with every pattern of the space, depth first, until it
+// returns false
+func (s *patternSpace) walk(fn func(sequence []int) bool) {
+	sequence := make([]int, 0, s.maxLength)
+	var extend func(visited dotSet) bool
+	extend = func(visited dotSet) bool {
+		if len(sequence) >= s.minLength && !fn(sequence) {
+			return false
+		}
+		if len(sequence) == s.maxLength {
+			return true
+		}
+		from := sequence[len(sequence)-1]
+		for to := 0; to < s.mode.Dots(); to++ {
+			if !s.canMove(visited, from, to) {
+				continue
+			}
+			sequence = append(sequence, to)
+			more := extend(visited.with(to))
+			sequence = sequence[:len(sequence)-1]
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+
+	for start := 0; start < s.mode.Dots(); start++ {
+		sequence = append(sequence[:0], start)
+		if !extend(dotSet{}.with(start)) {
+			return
+		}
+	}
+}
+
+// the patterns that continue the visited dots from the last one,
+// including the visited dots alone, memoized by visited dots and last
+func (s *patternSpace) countFrom(visited dotSet, last int, memo map[countKey]uint64) uint64 {
+	key := countKey{visited: visited, last: last}
+	if count, found := memo[key]; found {
+		return count
+	}
+
+	var count uint64
+	length := visited.count()
+	if length >= s.minLength {
+		count++
+	}
+	if length < s.maxLength {
+		for to := 0; to < s.mode.Dots(); to++ {
+			if s.canMove(visited, last, to) {
+				count += s.countFrom(visited.with(to), to, memo)
+			}
+		}
+	}
+	memo[key] = count
+	return count
+}
@@ -0,0 +1,204 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the space of drawable patterns: the known Android counts,
+ * counting with and without rules, the enumeration and the random
+ * pattern generator.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestCountPatterns(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      PatternMode
+		minLength int
+		maxLength int
+		rules     PatternRule
+		want      uint64
+	}{
+		// the well known counts of an Android 3x3 lock
+		{"android", PatternMode3x3, 4, 9, nil, 389112},
+		{"one dot", PatternMode3x3, 1, 1, PatternRules{}, 9},
+		{"two dots", PatternMode3x3, 2, 2, PatternRules{}, 56},
+		{"three dots", PatternMode3x3, 3, 3, nil, 320},
+		{"four dots", PatternMode3x3, 4, 4, nil, 1624},
+		{"five dots", PatternMode3x3, 5, 5, nil, 7152},
+		{"six dots", PatternMode3x3, 6, 6, nil, 26016},
+		{"seven dots", PatternMode3x3, 7, 7, nil, 72912},
+		{"eight dots", PatternMode3x3, 8, 8, nil, 140704},
+		{"nine dots", PatternMode3x3, 9, 9, nil, 140704},
+		{"default lengths", PatternMode3x3, 0, 0, nil, 389112 + 320},
+		// nil rules are the defaults, at least the shortest side
+		{"default rules", PatternMode3x3, 1, 3, nil, 320},
+		{"default rules too short", PatternMode3x3, 1, 2, nil, 0},
+		{"default rules listed", PatternMode3x3, 1, 4, DefaultPatternRules, 320 + 1624},
+		// the rules path lists every pattern, it must agree
+		{"rules accepting all", PatternMode3x3, 4, 9, MinLength(1), 389112},
+		{"rules refusing some", PatternMode3x3, 4, 9, MaxLength(5), 1624 + 7152},
+		{"4x4 two dots", PatternMode4x4, 2, 2, PatternRules{}, 172},
+		{"4x4 two dots listed", PatternMode4x4, 2, 2, MinLength(1), 172},
+		{"4x4 default rules", PatternMode4x4, 2, 3, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CountPatterns(tt.mode, tt.minLength, tt.maxLength, tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("CountPatterns(%s, %d, %d) = %d, want %d", tt.mode, tt.minLength, tt.maxLength, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountPatternsArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      PatternMode
+		minLength int
+		maxLength int
+	}{
+		{"no mode", PatternModeNone, 4, 9},
+		{"min over max", PatternMode3x3, 6, 5},
+		{"more dots than the grid", PatternMode3x3, 4, 10},
+	}
+	for _, tt := range tests {
+		if _, err := CountPatterns(tt.mode, tt.minLength, tt.maxLength, nil); err == nil {
+			t.Errorf("%s: CountPatterns() accepted %d..%d for %s", tt.name, tt.minLength, tt.maxLength, tt.mode)
+		}
+	}
+}
+
+func TestEnumeratePatterns(t *testing.T) {
+	seen := make(map[string]bool)
+	drawable := PatternRules{NoRevisits(), NoJumps()}
+	err := EnumeratePatterns(PatternMode3x3, 4, 4, nil, func(sequence []int) bool {
+		key := PatternInfoString(PatternMode3x3, sequence)
+		if seen[key] {
+			t.Errorf("%s enumerated twice", key)
+		}
+		seen[key] = true
+		if err := drawable.Check(sequence, PatternMode3x3); err != nil {
+			t.Errorf("%s is not drawable: %v", key, err)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 1624 {
+		t.Errorf("enumerated %d patterns of 4 dots, want 1624", len(seen))
+	}
+
+	// nil rules are the defaults, an empty list accepts any
+	for _, tt := range []struct {
+		rules PatternRule
+		want  int
+	}{{nil, 320}, {PatternRules{}, 9 + 56 + 320}} {
+		count := 0
+		EnumeratePatterns(PatternMode3x3, 1, 3, tt.rules, func(sequence []int) bool {
+			count++
+			return true
+		})
+		if count != tt.want {
+			t.Errorf("enumerated %d patterns of 1 to 3 dots with the rules %v, want %d", count, tt.rules, tt.want)
+		}
+	}
+
+	calls := 0
+	EnumeratePatterns(PatternMode3x3, 4, 9, nil, func([]int) bool {
+		calls++
+		return calls < 10
+	})
+	if calls != 10 {
+		t.Errorf("the enumeration went on for %d patterns after being stopped at 10", calls)
+	}
+}
+
+func TestGeneratePattern(t *testing.T) {
+	drawable := PatternRules{NoRevisits(), NoJumps()}
+	tests := []struct {
+		name      string
+		mode      PatternMode
+		minLength int
+		maxLength int
+		rules     PatternRule
+	}{
+		{"any", PatternMode3x3, 0, 0, nil},
+		{"exact length", PatternMode3x3, 6, 6, nil},
+		{"4x4", PatternMode4x4, 5, 8, nil},
+		{"rules", PatternMode3x3, 5, 9, PatternRules{MinDirectionChanges(3), DenyShapes()}},
+		{"short", PatternMode3x3, 1, 2, PatternRules{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := rand.NewPCG(1, 2)
+			for range 50 {
+				pattern, err := GeneratePattern(tt.mode, tt.minLength, tt.maxLength, tt.rules, src)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sequence := pattern.Pattern()
+				minLength, maxLength := tt.minLength, tt.maxLength
+				if minLength <= 0 || tt.rules == nil {
+					minLength = max(minLength, tt.mode.minSide())
+				}
+				if maxLength <= 0 {
+					maxLength = tt.mode.Dots()
+				}
+				if len(sequence) < minLength || len(sequence) > maxLength {
+					t.Errorf("%v has %d dots, want %d..%d", sequence, len(sequence), minLength, maxLength)
+				}
+				if err := drawable.Check(sequence, tt.mode); err != nil {
+					t.Errorf("%v is not drawable: %v", sequence, err)
+				}
+				if tt.rules != nil {
+					if err := tt.rules.Check(sequence, tt.mode); err != nil {
+						t.Errorf("%v breaks the rules: %v", sequence, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePatternSeeded(t *testing.T) {
+	generate := func(seed uint64) [][]int {
+		src := rand.NewPCG(seed, 0)
+		patterns := make([][]int, 0, 5)
+		for range 5 {
+			pattern, err := GeneratePattern(PatternMode4x4, 0, 0, nil, src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			patterns = append(patterns, pattern.Pattern())
+		}
+		return patterns
+	}
+	if first, again := generate(7), generate(7); !slices.EqualFunc(first, again, slices.Equal) {
+		t.Errorf("the same seed gave %v and %v", first, again)
+	}
+	if first, other := generate(7), generate(8); slices.EqualFunc(first, other, slices.Equal) {
+		t.Errorf("two seeds gave the same patterns %v", first)
+	}
+	if _, err := GeneratePattern(PatternMode3x3, 4, 9, MinLength(10), rand.NewPCG(1, 1)); !errors.Is(err, ErrNoPattern) {
+		t.Errorf("GeneratePattern() error = %v for impossible rules, want ErrNoPattern", err)
+	}
+	// nil rules are the defaults, no pattern of 3x3 is that short
+	if _, err := GeneratePattern(PatternMode3x3, 1, 2, nil, rand.NewPCG(1, 1)); !errors.Is(err, ErrNoPattern) {
+		t.Errorf("GeneratePattern() error = %v for 1 to 2 dots with the default rules, want ErrNoPattern", err)
+	}
+}
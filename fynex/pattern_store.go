@@ -0,0 +1,316 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Persistent storage of the patterns of several users. A store only
+ * ever holds salted hashes, with the time each one was saved and
+ * last used. This one keeps them in the fyne.Preferences, see the
+ * file store for an encrypted file.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	storeKEY_USERS   = ".users"
+	storeKEY_USER    = ".user."
+	storeKEY_HASH    = ".hash"
+	storeKEY_CREATED = ".created"
+	storeKEY_USED    = ".used"
+)
+
+// the store has no pattern for the user
+var ErrUnknownUser = errors.New("no pattern stored for the user")
+
+/* -----------------------------------------------------------------
+ *                     I N T E R F A C E S
+ * -----------------------------------------------------------------*/
+
+// A PatternStore keeps the pattern hash of every user. The methods
+// are safe for concurrent use.
+type PatternStore interface {
+	// stores the pattern of the user, replacing any previous one
+	Save(userID string, hash *PatternHash) error
+	// the pattern of the user, ErrUnknownUser if there is none
+	Load(userID string) (*PatternRecord, error)
+	// forgets the pattern of the user, ErrUnknownUser if there is none
+	Delete(userID string) error
+	// every stored pattern sorted by user ID
+	List() ([]PatternRecord, error)
+	// records that the pattern of the user was drawn at that time
+	MarkUsed(userID string, at time.Time) error
+}
+
+var _ PatternStore = (*PreferencesPatternStore)(nil)
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// The stored pattern of a user
+type PatternRecord struct {
+	UserID   string
+	Hash     *PatternHash
+	Created  time.Time // when the pattern was saved
+	LastUsed time.Time // zero if it was never drawn
+}
+
+// A PatternStore in the application preferences under a key prefix
+type PreferencesPatternStore struct {
+	prefs fyne.Preferences
+	key   string
+	mux   sync.Mutex
+}
+
+/* -----------------------------------------------------------------
+ *                  C O N S T R U C T O R S
+ * -----------------------------------------------------------------*/
+
+// (ctor) a store in the preferences under the given key prefix (for
+// example "lock.users"), e.g. fyne.CurrentApp().Preferences()
+func NewPreferencesPatternStore(prefs fyne.Preferences, key string) *PreferencesPatternStore {
+	return &PreferencesPatternStore{prefs: prefs, key: key}
+}
+
+// (ctor) a Lock Pattern widget that validates against the pattern of
+// the user in the store, see SetValidStoreEntry().
+func NewPatternLockWithStore(store PatternStore, userID string, onValidated func(bool)) (*PatternLock, error) {
+	record, err := store.Load(userID)
+	if err != nil {
+		return nil, err
+	}
+	pl := NewPatternLockWithHash(record.Hash, onValidated)
+	pl.setStoreEntry(store, record)
+	return pl, nil
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// implements PatternStore
+func (s *PreferencesPatternStore) Save(userID string, hash *PatternHash) error {
+	if userID == "" {
+		return ErrNoUserID
+	}
+	if hash == nil {
+		return errors.New("cannot store a nil pattern hash")
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	key := s.userKey(userID)
+	s.prefs.SetString(key+storeKEY_HASH, hash.String())
+	s.prefs.SetString(key+storeKEY_CREATED, formatStoreTime(time.Now()))
+	s.prefs.RemoveValue(key + storeKEY_USED)
+	if users := s.users(); !slices.Contains(users, userID) {
+		s.prefs.SetStringList(s.key+storeKEY_USERS, append(users, userID))
+	}
+	return nil
+}
+
+// implements PatternStore
+func (s *PreferencesPatternStore) Load(userID string) (*PatternRecord, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if !slices.Contains(s.users(), userID) {
+		return nil, ErrUnknownUser
+	}
+	return s.load(userID)
+}
+
+// implements PatternStore
+func (s *PreferencesPatternStore) Delete(userID string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	users := s.users()
+	if !slices.Contains(users, userID) {
+		return ErrUnknownUser
+	}
+	key := s.userKey(userID)
+	s.prefs.RemoveValue(key + storeKEY_HASH)
+	s.prefs.RemoveValue(key + storeKEY_CREATED)
+	s.prefs.RemoveValue(key + storeKEY_USED)
+	s.prefs.SetStringList(s.key+storeKEY_USERS, slices.DeleteFunc(users, func(user string) bool {
+		return user == userID
+	}))
+	return nil
+}
+
+// implements PatternStore
+func (s *PreferencesPatternStore) List() ([]PatternRecord, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	users := s.users()
+	slices.Sort(users)
+	records := make([]PatternRecord, 0, len(users))
+	for _, user := range users {
+		record, err := s.load(user)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	return records, nil
+}
+
+// implements PatternStore
+func (s *PreferencesPatternStore) MarkUsed(userID string, at time.Time) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if !slices.Contains(s.users(), userID) {
+		return ErrUnknownUser
+	}
+	s.prefs.SetString(s.userKey(userID)+storeKEY_USED, formatStoreTime(at))
+	return nil
+}
+
+// Validates against the pattern of the user in the store, like
+// SetValidHash(). Every time it is drawn its last-used time is updated.
+// It fails with ErrPatternInUse if the pattern is the duress pattern or
+// belongs to an identity.
+func (p *PatternLock) SetValidStoreEntry(store PatternStore, userID string) error {
+	record, err := store.Load(userID)
+	if err != nil {
+		return err
+	}
+	if err := p.setValid(patternSecret{hash: record.Hash}); err != nil {
+		return err
+	}
+	p.setStoreEntry(store, record)
+	return nil
+}
+
+// Enrolls every user of the store as an identity, see EnrollIdentity().
+// Their last-used time is updated when they are identified.
+func (p *PatternLock) EnrollStore(store PatternStore) error {
+	records, err := store.List()
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := p.EnrollIdentityHash(record.UserID, record.Hash); err != nil {
+			return fmt.Errorf("cannot enroll '%s': %w", record.UserID, err)
+		}
+	}
+	p.mux.Lock()
+	p.identityStore = store
+	p.mux.Unlock()
+	return nil
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// the users with a pattern, the caller holds the lock
+func (s *PreferencesPatternStore) users() []string {
+	return s.prefs.StringList(s.key + storeKEY_USERS)
+}
+
+// the key prefix of the user
+func (s *PreferencesPatternStore) userKey(userID string) string {
+	return s.key + storeKEY_USER + userID
+}
+
+// the record of a listed user, the caller holds the lock
+func (s *PreferencesPatternStore) load(userID string) (*PatternRecord, error) {
+	key := s.userKey(userID)
+	hash, err := ParsePatternHash(s.prefs.String(key + storeKEY_HASH))
+	if err != nil {
+		return nil, fmt.Errorf("stored pattern of '%s': %w", userID, err)
+	}
+	return &PatternRecord{
+		UserID:   userID,
+		Hash:     hash,
+		Created:  parseStoreTime(s.prefs.String(key + storeKEY_CREATED)),
+		LastUsed: parseStoreTime(s.prefs.String(key + storeKEY_USED)),
+	}, nil
+}
+
+// remembers the store entry being validated against
+func (p *PatternLock) setStoreEntry(store PatternStore, record *PatternRecord) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.store = store
+	p.storeUser = record.UserID
+	p.storeHash = record.Hash
+}
+
+// Updates the last-used time of the pattern that was drawn, if it came
+// from a store. It is the valid pattern when userID is empty. A store
+// may write a file, so it is updated in the background.
+func (p *PatternLock) markStoreUsed(userID string) {
+	p.mux.Lock()
+	store := p.identityStore
+	if userID == "" {
+		store, userID = nil, p.storeUser
+		if p.hash != nil && p.hash == p.storeHash {
+			store = p.store
+		}
+	}
+	p.mux.Unlock()
+
+	if store == nil {
+		return
+	}
+	at := time.Now()
+	markUsed := func() {
+		if err := store.MarkUsed(userID, at); err != nil && !errors.Is(err, ErrUnknownUser) {
+			log.Print("Cannot mark the pattern as used: ", err)
+		}
+	}
+	if fyne.CurrentApp() == nil {
+		// no event loop to keep responsive
+		markUsed()
+		return
+	}
+	go markUsed()
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// Hashes the pattern with DefaultHashParams and saves it for the user,
+// the plaintext pattern is never stored.
+func SavePattern(store PatternStore, userID string, pinfo *PatternInfo) error {
+	hash, err := pinfo.Hash()
+	if err != nil {
+		return err
+	}
+	return store.Save(userID, hash)
+}
+
+// a time in the preferences, as Unix milliseconds
+func formatStoreTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
+
+// a time from the preferences, zero when absent
+func parseStoreTime(s string) time.Time {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
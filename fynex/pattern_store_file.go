@@ -0,0 +1,291 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * A PatternStore in a single file. The patterns are salted hashes,
+ * and with a key the whole file is sealed with AES-GCM so that the
+ * user IDs and the usage times are not readable either.
+ ********************************************************************/
+package fynex
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	// version of the store file layout
+	storeFILE_VERSION = 1
+	// starts an encrypted store file, it is also authenticated
+	storeFILE_MAGIC = "FYNEXPS1"
+)
+
+/* -----------------------------------------------------------------
+ *                     I N T E R F A C E S
+ * -----------------------------------------------------------------*/
+
+var _ PatternStore = (*FilePatternStore)(nil)
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// A PatternStore in a JSON file, optionally encrypted. Each change
+// rewrites the whole file, it is meant for tens of users, not for
+// thousands.
+type FilePatternStore struct {
+	path string
+	aead cipher.AEAD // nil when the file is not encrypted
+	mux  sync.Mutex
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// the content of a store file
+type patternStoreJSON struct {
+	Version int                 `json:"version"`
+	Records []patternRecordJSON `json:"records"`
+}
+
+// a PatternRecord in a store file
+type patternRecordJSON struct {
+	UserID   string    `json:"user"`
+	Hash     string    `json:"hash"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+/* -----------------------------------------------------------------
+ *                  C O N S T R U C T O R S
+ * -----------------------------------------------------------------*/
+
+// (ctor) a store in the file at path, which is created on the first
+// Save(). With a key of 16, 24 or 32 bytes (AES-128, 192 or 256) the
+// file is encrypted, with a nil key it is plain JSON. The file is
+// not read until it is used.
+func NewFilePatternStore(path string, key []byte) (*FilePatternStore, error) {
+	s := &FilePatternStore{path: path}
+	if key != nil {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("pattern store key: %w", err)
+		}
+		if s.aead, err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// implements PatternStore
+func (s *FilePatternStore) Save(userID string, hash *PatternHash) error {
+	if userID == "" {
+		return ErrNoUserID
+	}
+	if hash == nil {
+		return errors.New("cannot store a nil pattern hash")
+	}
+	return s.update(func(content *patternStoreJSON) error {
+		record := patternRecordJSON{UserID: userID, Hash: hash.String(), Created: time.Now()}
+		if at := content.find(userID); at >= 0 {
+			content.Records[at] = record
+		} else {
+			content.Records = append(content.Records, record)
+		}
+		return nil
+	})
+}
+
+// implements PatternStore
+func (s *FilePatternStore) Load(userID string) (*PatternRecord, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	content, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	at := content.find(userID)
+	if at < 0 {
+		return nil, ErrUnknownUser
+	}
+	return content.Records[at].record()
+}
+
+// implements PatternStore
+func (s *FilePatternStore) Delete(userID string) error {
+	return s.update(func(content *patternStoreJSON) error {
+		at := content.find(userID)
+		if at < 0 {
+			return ErrUnknownUser
+		}
+		content.Records = slices.Delete(content.Records, at, at+1)
+		return nil
+	})
+}
+
+// implements PatternStore
+func (s *FilePatternStore) List() ([]PatternRecord, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	content, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]PatternRecord, 0, len(content.Records))
+	for _, stored := range content.Records {
+		record, err := stored.record()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	slices.SortFunc(records, func(a, b PatternRecord) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+	return records, nil
+}
+
+// implements PatternStore
+func (s *FilePatternStore) MarkUsed(userID string, at time.Time) error {
+	return s.update(func(content *patternStoreJSON) error {
+		index := content.find(userID)
+		if index < 0 {
+			return ErrUnknownUser
+		}
+		content.Records[index].LastUsed = at
+		return nil
+	})
+}
+
+// the index of the record of the user, -1 if there is none
+func (c *patternStoreJSON) find(userID string) int {
+	return slices.IndexFunc(c.Records, func(r patternRecordJSON) bool {
+		return r.UserID == userID
+	})
+}
+
+// the stored record with its hash parsed
+func (r patternRecordJSON) record() (*PatternRecord, error) {
+	hash, err := ParsePatternHash(r.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("stored pattern of '%s': %w", r.UserID, err)
+	}
+	return &PatternRecord{UserID: r.UserID, Hash: hash, Created: r.Created, LastUsed: r.LastUsed}, nil
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// reads, changes and writes the file back unless change fails
+func (s *FilePatternStore) update(change func(*patternStoreJSON) error) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	content, err := s.read()
+	if err != nil {
+		return err
+	}
+	if err := change(content); err != nil {
+		return err
+	}
+	return s.write(content)
+}
+
+// the content of the file, empty if it does not exist yet. The caller
+// holds the lock.
+func (s *FilePatternStore) read() (*patternStoreJSON, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &patternStoreJSON{Version: storeFILE_VERSION, Records: []patternRecordJSON{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := bytes.HasPrefix(data, []byte(storeFILE_MAGIC))
+	switch {
+	case encrypted && s.aead == nil:
+		return nil, errors.New("pattern store file is encrypted, a key is needed")
+	case !encrypted && s.aead != nil:
+		return nil, errors.New("pattern store file is not encrypted")
+	case encrypted:
+		sealed := data[len(storeFILE_MAGIC):]
+		if len(sealed) < s.aead.NonceSize() {
+			return nil, errors.New("pattern store file is truncated")
+		}
+		nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+		if data, err = s.aead.Open(nil, nonce, ciphertext, []byte(storeFILE_MAGIC)); err != nil {
+			return nil, errors.New("pattern store file cannot be decrypted, wrong key or tampered")
+		}
+	}
+
+	content := &patternStoreJSON{}
+	if err := json.Unmarshal(data, content); err != nil {
+		return nil, fmt.Errorf("pattern store file: %w", err)
+	}
+	if content.Version != storeFILE_VERSION {
+		return nil, fmt.Errorf("unsupported pattern store version %d", content.Version)
+	}
+	return content, nil
+}
+
+// Replaces the file with the content. It is written to a temporary
+// file first so that a crash never leaves half a store behind. The
+// caller holds the lock.
+func (s *FilePatternStore) write(content *patternStoreJSON) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+	if s.aead != nil {
+		nonce := make([]byte, s.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return err
+		}
+		sealed := append([]byte(storeFILE_MAGIC), nonce...)
+		data = s.aead.Seal(sealed, nonce, data, []byte(storeFILE_MAGIC))
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name()) // fails once renamed
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), s.path)
+}
@@ -0,0 +1,233 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the pattern stores: the same contract for the preferences
+ * and file stores, the encryption and tamper detection of the file,
+ * and the last-used time updated by the widget.
+ ********************************************************************/
+package fynex
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+// an AES-128 key for the encrypted file stores
+var testStoreKey = []byte("0123456789abcdef")
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestPatternStores(t *testing.T) {
+	stores := []struct {
+		name string
+		open func(t *testing.T) PatternStore
+	}{
+		{"preferences", func(t *testing.T) PatternStore {
+			return NewPreferencesPatternStore(test.NewTempApp(t).Preferences(), "lock.users")
+		}},
+		{"plain file", func(t *testing.T) PatternStore {
+			return mustFileStore(t, filepath.Join(t.TempDir(), "patterns.json"), nil)
+		}},
+		{"encrypted file", func(t *testing.T) PatternStore {
+			return mustFileStore(t, filepath.Join(t.TempDir(), "patterns.bin"), testStoreKey)
+		}},
+	}
+	alice, _ := HashPattern(mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), testHashParams)
+	bob, _ := HashPattern(mustPattern(t, []int{6, 7, 8, 5, 2}, PatternMode3x3), testHashParams)
+
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			store := tt.open(t)
+			if _, err := store.Load("alice"); !errors.Is(err, ErrUnknownUser) {
+				t.Fatalf("Load() from an empty store error = %v, want ErrUnknownUser", err)
+			}
+			if err := store.Save("", alice); !errors.Is(err, ErrNoUserID) {
+				t.Errorf("Save() without user error = %v, want ErrNoUserID", err)
+			}
+			for user, hash := range map[string]*PatternHash{"bob": bob, "alice": alice} {
+				if err := store.Save(user, hash); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			record, err := store.Load("alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if record.Hash.String() != alice.String() || record.Created.IsZero() || !record.LastUsed.IsZero() {
+				t.Errorf("Load() = %+v", record)
+			}
+			used := time.Now().Truncate(time.Millisecond)
+			if err := store.MarkUsed("alice", used); err != nil {
+				t.Fatal(err)
+			}
+			if record, _ := store.Load("alice"); !record.LastUsed.Equal(used) {
+				t.Errorf("LastUsed = %s, want %s", record.LastUsed, used)
+			}
+			if err := store.MarkUsed("carol", used); !errors.Is(err, ErrUnknownUser) {
+				t.Errorf("MarkUsed() of an unknown user error = %v", err)
+			}
+
+			records, err := store.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 2 || records[0].UserID != "alice" || records[1].UserID != "bob" {
+				t.Errorf("List() = %+v, want alice and bob", records)
+			}
+
+			// saving again replaces the pattern and forgets the use
+			if err := store.Save("alice", bob); err != nil {
+				t.Fatal(err)
+			}
+			if record, _ := store.Load("alice"); record.Hash.String() != bob.String() || !record.LastUsed.IsZero() {
+				t.Errorf("replaced record = %+v", record)
+			}
+
+			if err := store.Delete("bob"); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Delete("bob"); !errors.Is(err, ErrUnknownUser) {
+				t.Errorf("second Delete() error = %v, want ErrUnknownUser", err)
+			}
+			if records, _ := store.List(); len(records) != 1 {
+				t.Errorf("List() after Delete() = %+v", records)
+			}
+		})
+	}
+}
+
+func TestFilePatternStoreTampering(t *testing.T) {
+	hash, _ := HashPattern(mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), testHashParams)
+	saved := func(t *testing.T, key []byte) string {
+		path := filepath.Join(t.TempDir(), "patterns")
+		if err := mustFileStore(t, path, key).Save("alice", hash); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	rewrite := func(t *testing.T, path string, change func([]byte) []byte) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, change(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		key     []byte // of the file
+		openKey []byte // to read it
+		change  func([]byte) []byte
+	}{
+		{"flipped byte", testStoreKey, testStoreKey, func(data []byte) []byte {
+			data[len(data)-5] ^= 0x01
+			return data
+		}},
+		{"flipped nonce", testStoreKey, testStoreKey, func(data []byte) []byte {
+			data[len(storeFILE_MAGIC)] ^= 0x01
+			return data
+		}},
+		{"truncated", testStoreKey, testStoreKey, func(data []byte) []byte {
+			return data[:len(storeFILE_MAGIC)+4]
+		}},
+		{"appended", testStoreKey, testStoreKey, func(data []byte) []byte {
+			return append(data, 0)
+		}},
+		{"wrong key", testStoreKey, []byte("fedcba9876543210"), nil},
+		{"encrypted without key", testStoreKey, nil, nil},
+		{"plain with a key", nil, testStoreKey, nil},
+		{"plain malformed", nil, nil, func(data []byte) []byte {
+			return data[:len(data)/2]
+		}},
+		{"plain version", nil, nil, func(data []byte) []byte {
+			return bytes.Replace(data, []byte(`"version": 1`), []byte(`"version": 2`), 1)
+		}},
+		{"plain hash", nil, nil, func(data []byte) []byte {
+			return bytes.Replace(data, []byte("pbkdf2"), []byte("md5"), 1)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := saved(t, tt.key)
+			if tt.change != nil {
+				rewrite(t, path, tt.change)
+			}
+			if _, err := mustFileStore(t, path, tt.openKey).Load("alice"); err == nil {
+				t.Error("the store file was read")
+			}
+		})
+	}
+}
+
+func TestFilePatternStoreEncrypted(t *testing.T) {
+	hash, _ := HashPattern(mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3), testHashParams)
+	for _, key := range [][]byte{nil, testStoreKey} {
+		path := filepath.Join(t.TempDir(), "patterns")
+		if err := mustFileStore(t, path, key).Save("alice", hash); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(path)
+		readable := bytes.Contains(data, []byte("alice"))
+		if readable != (key == nil) {
+			t.Errorf("encrypted %t, the user ID is readable %t", key != nil, readable)
+		}
+	}
+	if _, err := NewFilePatternStore("patterns", []byte("short")); err == nil {
+		t.Error("a 5 byte key was accepted")
+	}
+}
+
+func TestMarkStoreUsed(t *testing.T) {
+	test.NewTempApp(t)
+	store := mustFileStore(t, filepath.Join(t.TempDir(), "patterns"), testStoreKey)
+	if err := SavePattern(store, "alice", mustPattern(t, []int{0, 1, 2, 5, 8}, PatternMode3x3)); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewPatternLockWithStore(store, "alice", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the file is written in the background
+	p.markStoreUsed("")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		record, err := store.Load("alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !record.LastUsed.IsZero() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the last-used time was not updated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// a file store, the test fails if it cannot be opened
+func mustFileStore(t *testing.T, path string, key []byte) *FilePatternStore {
+	t.Helper()
+	store, err := NewFilePatternStore(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
@@ -0,0 +1,357 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Pattern strength analysis. Scores an unlock sequence by its length,
+ * direction changes, crossings, knight-style moves, revisits and an
+ * estimated entropy. It also flags the commonly used shapes that an
+ * attacker would try first.
+ ********************************************************************/
+package fynex
+
+import (
+	"fmt"
+	"math"
+)
+
+/* -----------------------------------------------------------------
+ *                       G L O B A L S
+ * -----------------------------------------------------------------*/
+
+const (
+	StrengthWeak StrengthLevel = iota
+	StrengthFair
+	StrengthGood
+	StrengthStrong
+)
+
+const (
+	ShapeLine   PatternShape = "line"   // a single straight row, column or diagonal
+	ShapeL      PatternShape = "L"      // two perpendicular strokes
+	ShapeZ      PatternShape = "Z"      // horizontal, diagonal, horizontal
+	ShapeN      PatternShape = "N"      // vertical, diagonal, vertical
+	ShapeU      PatternShape = "U"      // there and back again, like U or C
+	ShapeSquare PatternShape = "square" // four strokes going around
+)
+
+/* -----------------------------------------------------------------
+ *                  P U B L I C      T Y P E S
+ * -----------------------------------------------------------------*/
+
+// A coarse classification of the strength score
+type StrengthLevel uint8
+
+// A well-known pattern shape
+type PatternShape string
+
+// The result of a pattern strength analysis
+type PatternStrength struct {
+	Score            int            // 0..100
+	Level            StrengthLevel  // classification of Score
+	Length           int            // number of dots
+	DirectionChanges int            // times the drawing direction changes
+	Crossings        int            // segments crossing earlier segments
+	KnightMoves      int            // chess knight style segments (1x2)
+	Revisits         int            // dots visited more than once
+	Entropy          float64        // estimated bits of entropy
+	Shapes           []PatternShape // common shapes detected
+}
+
+/* -----------------------------------------------------------------
+ *                  P R I V A T E    T Y P E S
+ * -----------------------------------------------------------------*/
+
+// a step between two dots in grid coordinates
+type patternVector struct {
+	dx, dy int
+}
+
+/* -----------------------------------------------------------------
+ *                       M E T H O D S
+ * -----------------------------------------------------------------*/
+
+// implements fmt.Stringer
+func (sl StrengthLevel) String() string {
+	var result string
+	switch sl {
+	case StrengthWeak:
+		result = "Weak"
+	case StrengthFair:
+		result = "Fair"
+	case StrengthGood:
+		result = "Good"
+	case StrengthStrong:
+		result = "Strong"
+	default:
+		result = ""
+	}
+	return result
+}
+
+// the localizable name of the level, shown by the strength meter
+func (sl StrengthLevel) messageID() MessageID {
+	switch sl {
+	case StrengthFair:
+		return MsgStrengthFair
+	case StrengthGood:
+		return MsgStrengthGood
+	case StrengthStrong:
+		return MsgStrengthStrong
+	}
+	return MsgStrengthWeak
+}
+
+// analyze the strength of this pattern
+func (pi *PatternInfo) Strength() PatternStrength {
+	return AnalyzePattern(pi.pattern, pi.mode)
+}
+
+// implements fmt.Stringer
+func (ps PatternStrength) String() string {
+	return fmt.Sprintf("%s (%d/100, %.1f bits)", ps.Level, ps.Score, ps.Entropy)
+}
+
+// whether the given shape was detected
+func (ps PatternStrength) HasShape(shape PatternShape) bool {
+	for _, s := range ps.Shapes {
+		if s == shape {
+			return true
+		}
+	}
+	return false
+}
+
+// the unit step of a vector, e.g. (2,2) is (1,1) and (0,-2) is (0,-1)
+func (v patternVector) unit() patternVector {
+	g := gcd(abs(v.dx), abs(v.dy))
+	if g == 0 {
+		return v
+	}
+	return patternVector{v.dx / g, v.dy / g}
+}
+
+func (v patternVector) isHorizontal() bool {
+	return v.dy == 0 && v.dx != 0
+}
+
+func (v patternVector) isVertical() bool {
+	return v.dx == 0 && v.dy != 0
+}
+
+func (v patternVector) isDiagonal() bool {
+	return v.dx != 0 && abs(v.dx) == abs(v.dy)
+}
+
+func (v patternVector) isAxial() bool {
+	return v.isHorizontal() || v.isVertical()
+}
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+// Scores a sequence (internal format) for the given mode. The score
+// rewards length, direction changes, crossings, knight moves and
+// revisits, and is halved when a common shape is detected.
+func AnalyzePattern(sequence []int, mode PatternMode) PatternStrength {
+	result := PatternStrength{
+		Length: len(sequence),
+		Shapes: make([]PatternShape, 0),
+	}
+	width := mode.Width()
+	if !mode.IsValid() || len(sequence) == 0 {
+		return result
+	}
+
+	vectors := make([]patternVector, 0, len(sequence))
+	for i := 1; i < len(sequence); i++ {
+		v := patternVector{
+			dx: sequence[i]%width - sequence[i-1]%width,
+			dy: sequence[i]/width - sequence[i-1]/width,
+		}
+		vectors = append(vectors, v)
+		if (abs(v.dx) == 1 && abs(v.dy) == 2) || (abs(v.dx) == 2 && abs(v.dy) == 1) {
+			result.KnightMoves++
+		}
+	}
+
+	strokes := patternStrokes(vectors)
+	result.DirectionChanges = max(0, len(strokes)-1)
+	result.Crossings = countCrossings(sequence, width)
+	result.Revisits = countRevisits(sequence)
+	result.Entropy = estimateEntropy(sequence, mode)
+	result.Shapes = detectShapes(strokes)
+
+	// the length is scored relative to the grid, the minimum is worth 0
+	dots, shortest := mode.Dots(), mode.minSide()
+	if len(sequence) > shortest && dots > shortest {
+		result.Score += 30 * min(len(sequence)-shortest, dots-shortest) / (dots - shortest)
+	}
+	result.Score += 5 * min(result.DirectionChanges, 6)
+	result.Score += 5 * min(result.Crossings, 3)
+	result.Score += 5 * min(result.KnightMoves, 3)
+	result.Score += 5 * min(result.Revisits, 2)
+	if len(result.Shapes) != 0 {
+		result.Score /= 2
+	}
+	result.Score = min(result.Score, 100)
+
+	switch {
+	case result.Score >= 75:
+		result.Level = StrengthStrong
+	case result.Score >= 50:
+		result.Level = StrengthGood
+	case result.Score >= 25:
+		result.Level = StrengthFair
+	default:
+		result.Level = StrengthWeak
+	}
+
+	return result
+}
+
+// merge consecutive vectors going in the same direction into strokes
+// (unit directions)
+func patternStrokes(vectors []patternVector) []patternVector {
+	strokes := make([]patternVector, 0)
+	for _, v := range vectors {
+		u := v.unit()
+		if len(strokes) == 0 || strokes[len(strokes)-1] != u {
+			strokes = append(strokes, u)
+		}
+	}
+	return strokes
+}
+
+// recognize the common shapes from the drawing strokes
+func detectShapes(strokes []patternVector) []PatternShape {
+	shapes := make([]PatternShape, 0)
+	switch len(strokes) {
+	case 1:
+		shapes = append(shapes, ShapeLine)
+	case 2:
+		a, b := strokes[0], strokes[1]
+		if a.isAxial() && b.isAxial() && a.dx*b.dx+a.dy*b.dy == 0 {
+			shapes = append(shapes, ShapeL)
+		}
+	case 3:
+		a, b, c := strokes[0], strokes[1], strokes[2]
+		switch {
+		case a.isHorizontal() && a == c && b.isDiagonal():
+			shapes = append(shapes, ShapeZ)
+		case a.isVertical() && a == c && b.isDiagonal():
+			shapes = append(shapes, ShapeN)
+		case a.isAxial() && b.isAxial() && a.dx == -c.dx && a.dy == -c.dy:
+			shapes = append(shapes, ShapeU)
+		}
+	case 4:
+		if isBox(strokes) {
+			shapes = append(shapes, ShapeSquare)
+		}
+	}
+	return shapes
+}
+
+// all strokes are axial and every one of them turns the same way
+func isBox(strokes []patternVector) bool {
+	turn := 0
+	for i, stroke := range strokes {
+		if !stroke.isAxial() {
+			return false
+		}
+		if i == 0 {
+			continue
+		}
+		prev := strokes[i-1]
+		t := prev.dx*stroke.dy - prev.dy*stroke.dx
+		if t == 0 || (turn != 0 && t != turn) {
+			return false
+		}
+		turn = t
+	}
+	return true
+}
+
+// number of segments that properly cross a previous, non-adjacent one
+func countCrossings(sequence []int, width int) int {
+	point := func(index int) (int, int) {
+		return index % width, index / width
+	}
+	orientation := func(ax, ay, bx, by, cx, cy int) int {
+		cross := (bx-ax)*(cy-ay) - (by-ay)*(cx-ax)
+		switch {
+		case cross > 0:
+			return 1
+		case cross < 0:
+			return -1
+		}
+		return 0
+	}
+
+	crossings := 0
+	for j := 2; j < len(sequence)-1; j++ {
+		cx, cy := point(sequence[j])
+		dx, dy := point(sequence[j+1])
+		for i := 0; i < j-1; i++ {
+			ax, ay := point(sequence[i])
+			bx, by := point(sequence[i+1])
+			o1 := orientation(ax, ay, bx, by, cx, cy)
+			o2 := orientation(ax, ay, bx, by, dx, dy)
+			o3 := orientation(cx, cy, dx, dy, ax, ay)
+			o4 := orientation(cx, cy, dx, dy, bx, by)
+			if o1*o2 < 0 && o3*o4 < 0 {
+				crossings++
+			}
+		}
+	}
+	return crossings
+}
+
+// number of extra visits to dots that were already part of the pattern
+func countRevisits(sequence []int) int {
+	seen := make(map[int]bool)
+	revisits := 0
+	for _, index := range sequence {
+		if seen[index] {
+			revisits++
+		}
+		seen[index] = true
+	}
+	return revisits
+}
+
+// Estimates the entropy in bits as the sum of log2 of the number of
+// dots that could have been chosen at every step: any dot at first, then
+// any unvisited dot that is not hidden behind another unvisited one.
+func estimateEntropy(sequence []int, mode PatternMode) float64 {
+	width, dots := mode.Width(), mode.Dots()
+	visited := make(map[int]bool)
+	bits := 0.0
+	for i, index := range sequence {
+		choices := 0
+		if i == 0 {
+			choices = dots
+		} else {
+			for candidate := 0; candidate < dots; candidate++ {
+				if visited[candidate] || candidate == sequence[i-1] {
+					continue
+				}
+				reachable := true
+				for _, mid := range intermediateDots(sequence[i-1], candidate, width) {
+					if !visited[mid] {
+						reachable = false
+						break
+					}
+				}
+				if reachable {
+					choices++
+				}
+			}
+		}
+		if choices > 1 {
+			bits += math.Log2(float64(choices))
+		}
+		visited[index] = true
+	}
+	return bits
+}
@@ -0,0 +1,171 @@
+/* *****************************************************************
+ *              Copyright(C)2026 Lord of Scripts
+ *                      All Rights Reserved
+ * -----------------------------------------------------------------
+ * Tests of the pattern strength analysis: the score and its levels,
+ * the common shapes, crossings, knight moves and revisits, and the
+ * live strength meter of the widget in design state.
+ ********************************************************************/
+package fynex
+
+import (
+	"errors"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+/* -----------------------------------------------------------------
+ *                       F U N C T I O N S
+ * -----------------------------------------------------------------*/
+
+func TestAnalyzePatternScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		sequence  []int
+		wantScore int
+		wantLevel StrengthLevel
+	}{
+		{"empty", nil, 0, StrengthWeak},
+		{"shortest", []int{0, 1, 2}, 0, StrengthWeak},
+		// the levels start at 25, 50 and 75
+		{"below fair", []int{0, 1, 2, 4, 3}, 20, StrengthWeak},
+		{"fair", []int{0, 1, 2, 3, 4}, 25, StrengthFair},
+		{"below good", []int{0, 1, 2, 3, 4, 5, 6}, 45, StrengthFair},
+		{"good", []int{0, 1, 2, 3, 4, 5, 7, 8}, 50, StrengthGood},
+		{"below strong", []int{0, 1, 2, 3, 4, 6, 5, 7, 8}, 70, StrengthGood},
+		{"strong", []int{0, 1, 2, 3, 4, 7, 5, 6, 8}, 75, StrengthStrong},
+		{"stronger", []int{0, 1, 2, 3, 8, 4, 6, 5, 7}, 90, StrengthStrong},
+		// a shape halves the score
+		{"square", []int{0, 1, 2, 5, 8, 7, 6, 3}, 20, StrengthWeak},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnalyzePattern(tt.sequence, PatternMode3x3)
+			if got.Score != tt.wantScore || got.Level != tt.wantLevel {
+				t.Errorf("AnalyzePattern(%v) = %s, want %d %s", tt.sequence, got, tt.wantScore, tt.wantLevel)
+			}
+			if got.Length != len(tt.sequence) {
+				t.Errorf("Length = %d, want %d", got.Length, len(tt.sequence))
+			}
+		})
+	}
+	if got := AnalyzePattern([]int{0, 1, 2}, PatternModeNone); got.Score != 0 || got.Entropy != 0 {
+		t.Errorf("AnalyzePattern() without a mode = %s", got)
+	}
+}
+
+func TestAnalyzePatternShapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		sequence []int
+		want     PatternShape // "" for none
+	}{
+		{"row", []int{0, 1, 2}, ShapeLine},
+		{"diagonal", []int{0, 4, 8}, ShapeLine},
+		{"L", []int{0, 3, 6, 7, 8}, ShapeL},
+		{"Z", []int{0, 1, 2, 4, 6, 7, 8}, ShapeZ},
+		{"N", []int{6, 3, 0, 4, 8, 5, 2}, ShapeN},
+		{"U", []int{0, 3, 6, 7, 8, 5, 2}, ShapeU},
+		{"C", []int{2, 1, 0, 3, 6, 7, 8}, ShapeU},
+		{"square", []int{0, 1, 2, 5, 8, 7, 6, 3}, ShapeSquare},
+		{"three sides", []int{0, 1, 4, 3}, ShapeU},
+		{"closed square", []int{0, 1, 4, 3, 0}, ShapeSquare},
+		{"diagonal turn", []int{0, 4, 8, 5}, ""},
+		{"snake", []int{0, 1, 2, 5, 4, 3, 6, 7, 8}, ""},
+		{"zigzag", []int{0, 4, 2, 5, 6}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnalyzePattern(tt.sequence, PatternMode3x3)
+			switch {
+			case tt.want == "" && len(got.Shapes) != 0:
+				t.Errorf("AnalyzePattern(%v) shapes = %v, want none", tt.sequence, got.Shapes)
+			case tt.want != "" && (len(got.Shapes) != 1 || !got.HasShape(tt.want)):
+				t.Errorf("AnalyzePattern(%v) shapes = %v, want %s", tt.sequence, got.Shapes, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzePatternMoves(t *testing.T) {
+	tests := []struct {
+		name          string
+		sequence      []int
+		wantChanges   int
+		wantCrossings int
+		wantKnights   int
+		wantRevisits  int
+	}{
+		{"straight", []int{0, 1, 2}, 0, 0, 0, 0},
+		{"L", []int{0, 1, 2, 5, 8}, 1, 0, 0, 0},
+		{"X", []int{0, 8, 2, 6}, 2, 1, 0, 0},
+		{"through the center", []int{0, 8, 2, 6, 1, 7}, 4, 4, 1, 0},
+		{"touching", []int{0, 4, 2}, 1, 0, 0, 0},
+		{"knights", []int{0, 5, 6}, 1, 0, 2, 0},
+		{"revisit", []int{0, 1, 4, 1, 2}, 3, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnalyzePattern(tt.sequence, PatternMode3x3)
+			if got.DirectionChanges != tt.wantChanges || got.Crossings != tt.wantCrossings ||
+				got.KnightMoves != tt.wantKnights || got.Revisits != tt.wantRevisits {
+				t.Errorf("AnalyzePattern(%v) = changes %d crossings %d knights %d revisits %d, want %d %d %d %d",
+					tt.sequence, got.DirectionChanges, got.Crossings, got.KnightMoves, got.Revisits,
+					tt.wantChanges, tt.wantCrossings, tt.wantKnights, tt.wantRevisits)
+			}
+		})
+	}
+}
+
+func TestDemoPatternsAreWeak(t *testing.T) {
+	// the default patterns of the demo application
+	tests := []struct {
+		mode     PatternMode
+		sequence []int
+	}{
+		{PatternMode3x3, []int{0, 1, 2, 5, 8}},
+		{PatternMode4x4, []int{0, 1, 2, 3, 7, 11, 15}},
+		{PatternMode5x5, []int{0, 1, 2, 3, 4, 9, 14, 19, 24}},
+	}
+	for _, tt := range tests {
+		got := mustPattern(t, tt.sequence, tt.mode).Strength()
+		if got.Level != StrengthWeak || !got.HasShape(ShapeL) {
+			t.Errorf("the %s default pattern is %s with shapes %v, want Weak and an L", tt.mode, got, got.Shapes)
+		}
+	}
+}
+
+func TestStrengthMeter(t *testing.T) {
+	test.NewTempApp(t)
+	p := NewPatternLockFor(PatternMode3x3, nil)
+	w := test.NewTempWindow(t, p)
+	w.Resize(fyne.NewSize(300, 360))
+	r := test.TempWidgetRenderer(t, p).(*patternRenderer)
+
+	var failed error
+	p.OnDefineFailed = func(err error) { failed = err }
+	p.SetStrengthMeter(true, StrengthFair)
+	p.EnterDesignState()
+
+	for _, dot := range []int{0, 1, 2, 5, 8} {
+		p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: p.DotCenter(dot)}})
+	}
+	r.Refresh()
+	if !r.meterText.Visible() || r.meterText.Text != p.message(MsgStrengthWeak) {
+		t.Errorf("meter visible %t with %q, want it to show Weak", r.meterText.Visible(), r.meterText.Text)
+	}
+	p.DragEnd()
+	if !errors.Is(failed, ErrPatternTooWeak) {
+		t.Errorf("OnDefineFailed() got %v, want ErrPatternTooWeak", failed)
+	}
+
+	// only while designing
+	p.CancelDesign()
+	p.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: p.DotCenter(0)}})
+	r.Refresh()
+	if r.meterText.Visible() {
+		t.Error("the meter is shown outside of design state")
+	}
+}